@@ -0,0 +1,306 @@
+package pom_component_parsing
+
+import (
+	"sort"
+	"strings"
+)
+
+// ComparableVersion 是 Maven ComparableVersion（maven-artifact）的 Go 实现，用于按 Maven 的规则比较版本
+//
+// 版本按 '.'、'-' 以及数字与字母的交界拆分为若干项：
+//   - 数字项按数值比较，末尾的 0 会被忽略，因此 1 = 1.0 = 1.0.0
+//   - 字符串项按限定符排序：alpha < beta < milestone < rc = cr < snapshot < "" = ga = final = release < sp，
+//     未知限定符大于所有已知限定符，彼此之间按字典序比较
+//   - 紧跟数字的 a、b、m 分别视为 alpha、beta、milestone，如 1.0a1 = 1.0-alpha-1
+type ComparableVersion struct {
+	value string
+	items listItem
+}
+
+// versionItem 表示版本中的一项
+type versionItem interface {
+	compareTo(other versionItem) int // other 为 nil 时与“空”项比较
+	isNull() bool
+}
+
+// intItem 表示数字项，value 为去除前导 0 的十进制数字串，以支持任意长度的数字
+type intItem string
+
+// stringItem 表示限定符项
+type stringItem string
+
+// listItem 表示以 '-' 分隔的子列表
+type listItem []versionItem
+
+// qualifiers 是已知限定符的顺序，空字符串表示正式版本
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// qualifierAliases 是限定符的别名
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// releaseVersionIndex 是正式版本（空限定符）在 qualifiers 中的位置
+var releaseVersionIndex = comparableQualifier("")
+
+// ParseComparableVersion 解析版本字符串
+func ParseComparableVersion(version string) ComparableVersion {
+	return ComparableVersion{value: version, items: parseVersionItems(version)}
+}
+
+// Compare 比较两个版本，返回 -1、0、1
+func (v ComparableVersion) Compare(other ComparableVersion) int {
+	return v.items.compareTo(other.items)
+}
+
+// String 返回原始版本字符串
+func (v ComparableVersion) String() string {
+	return v.value
+}
+
+// CompareVersions 按 Maven 的规则比较两个版本字符串，返回 -1、0、1
+func CompareVersions(a, b string) int {
+	return ParseComparableVersion(a).Compare(ParseComparableVersion(b))
+}
+
+// SortVersions 按 Maven 的规则对版本字符串升序排序
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) < 0
+	})
+}
+
+// LatestVersion 返回按 Maven 规则最新的版本，versions 为空时返回空字符串
+func LatestVersion(versions ...string) string {
+	var latest string
+	for i, v := range versions {
+		if i == 0 || CompareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// parseVersionItems 将版本字符串拆分为版本项，算法与 Maven ComparableVersion.parseVersion 一致
+func parseVersionItems(version string) listItem {
+	version = strings.ToLower(version)
+
+	root := &listItem{}
+	list := root
+	stack := []*listItem{root}
+
+	// newList 在当前列表中追加一个子列表并将其作为当前列表
+	newList := func() {
+		child := &listItem{}
+		*list = append(*list, child)
+		list = child
+		stack = append(stack, child)
+	}
+
+	isDigit := false
+	startIndex := 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.':
+			if i == startIndex {
+				*list = append(*list, intItem(""))
+			} else {
+				*list = append(*list, parseVersionItem(isDigit, version[startIndex:i]))
+			}
+			startIndex = i + 1
+		case c == '-':
+			if i == startIndex {
+				*list = append(*list, intItem(""))
+			} else {
+				*list = append(*list, parseVersionItem(isDigit, version[startIndex:i]))
+			}
+			startIndex = i + 1
+			newList()
+		case c >= '0' && c <= '9':
+			if !isDigit && i > startIndex {
+				*list = append(*list, newStringItem(version[startIndex:i], true))
+				startIndex = i
+				newList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > startIndex {
+				*list = append(*list, parseVersionItem(true, version[startIndex:i]))
+				startIndex = i
+				newList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > startIndex {
+		*list = append(*list, parseVersionItem(isDigit, version[startIndex:]))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return root.resolve()
+}
+
+// parseVersionItem 根据是否为数字创建版本项
+func parseVersionItem(isDigit bool, buf string) versionItem {
+	if isDigit {
+		return intItem(strings.TrimLeft(buf, "0"))
+	}
+	return newStringItem(buf, false)
+}
+
+// newStringItem 创建限定符项，followedByDigit 为 true 时 a、b、m 分别视为 alpha、beta、milestone
+func newStringItem(value string, followedByDigit bool) stringItem {
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[value]; ok {
+		value = alias
+	}
+	return stringItem(value)
+}
+
+// comparableQualifier 返回限定符用于比较的字符串，未知限定符排在所有已知限定符之后
+func comparableQualifier(qualifier string) string {
+	for i, q := range qualifiers {
+		if q == qualifier {
+			return string(rune('0' + i))
+		}
+	}
+	return string(rune('0'+len(qualifiers))) + "-" + qualifier
+}
+
+func (i intItem) isNull() bool {
+	return i == ""
+}
+
+func (i intItem) compareTo(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case intItem:
+		// 去除前导 0 后，位数多的数值更大，位数相同时按字典序比较
+		if len(i) != len(o) {
+			if len(i) < len(o) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(string(i), string(o))
+	case stringItem:
+		return 1 // 1.1 > 1-sp
+	default:
+		return 1 // 1.1 > 1-1
+	}
+}
+
+func (s stringItem) isNull() bool {
+	return comparableQualifier(string(s)) == releaseVersionIndex
+}
+
+func (s stringItem) compareTo(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-ga = 1, 1-sp > 1
+		return strings.Compare(comparableQualifier(string(s)), releaseVersionIndex)
+	case intItem:
+		return -1 // 1.any < 1.1
+	case stringItem:
+		return strings.Compare(comparableQualifier(string(s)), comparableQualifier(string(o)))
+	default:
+		return -1 // 1.any < 1-1
+	}
+}
+
+// normalize 去除列表末尾的空项（0、正式版本限定符、空列表），遇到非列表的非空项时停止
+func (l *listItem) normalize() {
+	for i := len(*l) - 1; i >= 0; i-- {
+		item := (*l)[i]
+		if child, ok := item.(*listItem); ok {
+			if len(*child) == 0 {
+				*l = append((*l)[:i], (*l)[i+1:]...)
+			}
+			continue
+		}
+		if item.isNull() {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+			continue
+		}
+		break
+	}
+}
+
+// resolve 将解析时使用的 *listItem 子列表递归转换为 listItem 值
+func (l *listItem) resolve() listItem {
+	rs := make(listItem, 0, len(*l))
+	for _, item := range *l {
+		if child, ok := item.(*listItem); ok {
+			rs = append(rs, child.resolve())
+			continue
+		}
+		rs = append(rs, item)
+	}
+	return rs
+}
+
+func (l listItem) isNull() bool {
+	return len(l) == 0
+}
+
+func (l listItem) compareTo(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-0 = 1- (normalize) = 1；与 Maven 3.8 起（MNG-6964）一致，依次比较所有元素，如 1-0.1 > 1
+		for _, item := range l {
+			if result := item.compareTo(nil); result != 0 {
+				return result
+			}
+		}
+		return 0
+	case intItem:
+		return -1 // 1-1 < 1.0.x
+	case stringItem:
+		return 1 // 1-1 > 1-sp
+	case listItem:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right versionItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+
+			var result int
+			switch {
+			case left == nil && right == nil:
+				result = 0
+			case left == nil:
+				result = -1 * right.compareTo(nil)
+			default:
+				result = left.compareTo(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,101 @@
+package pom_component_parsing
+
+import (
+	"reflect"
+	"testing"
+)
+
+// checkVersionsOrder 检查版本列表严格递增
+func checkVersionsOrder(t *testing.T, versions []string) {
+	t.Helper()
+	for i := 1; i < len(versions); i++ {
+		for j := i; j < len(versions); j++ {
+			low, high := versions[i-1], versions[j]
+			if CompareVersions(low, high) >= 0 {
+				t.Errorf("期望 %s < %s", low, high)
+			}
+			if CompareVersions(high, low) <= 0 {
+				t.Errorf("期望 %s > %s", high, low)
+			}
+		}
+	}
+}
+
+func TestComparableVersion_QualifierOrder(t *testing.T) {
+	checkVersionsOrder(t, []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+		"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+		"1-1", "1-2", "1-123",
+	})
+}
+
+func TestComparableVersion_NumberOrder(t *testing.T) {
+	checkVersionsOrder(t, []string{
+		"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1",
+		"2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+	})
+}
+
+func TestComparableVersion_MNG6964(t *testing.T) {
+	checkVersionsOrder(t, []string{"1-0.alpha", "1"})
+	checkVersionsOrder(t, []string{"1-0.beta", "1"})
+	checkVersionsOrder(t, []string{"1", "1-0.1"})
+	checkVersionsOrder(t, []string{"1", "1-1"})
+}
+
+func TestComparableVersion_Equal(t *testing.T) {
+	groups := [][]string{
+		{"1", "1.0", "1.0.0", "1-0", "1.0-0", "1-ga", "1-final", "1-release", "1.0-GA"},
+		{"1a", "1-a", "1.0-a", "1.0.0-a"},
+		{"1a1", "1-alpha-1", "1.0-alpha1"},
+		{"1b2", "1-beta-2", "1.0-beta2"},
+		{"1m3", "1-milestone-3", "1.0-milestone3"},
+		{"1cr", "1rc", "1-rc", "1.0-RC"},
+		{"1.0.0-SNAPSHOT", "1-snapshot", "1.0-Snapshot"},
+		{"2.0.0001", "2.0.1"},
+	}
+
+	for _, group := range groups {
+		for _, a := range group {
+			for _, b := range group {
+				if CompareVersions(a, b) != 0 {
+					t.Errorf("期望 %s = %s", a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestComparableVersion_LargeNumbers(t *testing.T) {
+	checkVersionsOrder(t, []string{"1.9", "1.10", "2147483648", "9223372036854775808", "92233720368547758080"})
+}
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{name: "数字比较", versions: []string{"1.9.0", "1.10.0", "1.2.0"}, want: "1.10.0"},
+		{name: "正式版本大于预发布版本", versions: []string{"1.0-rc1", "1.0", "1.0-alpha"}, want: "1.0"},
+		{name: "空列表", versions: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LatestVersion(tt.versions...); got != tt.want {
+				t.Errorf("LatestVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10.0", "1.0", "1.9.0", "1.0-alpha", "1.0-SNAPSHOT"}
+	SortVersions(versions)
+
+	want := []string{"1.0-alpha", "1.0-SNAPSHOT", "1.0", "1.9.0", "1.10.0"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("SortVersions() = %v, want %v", versions, want)
+	}
+}
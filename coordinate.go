@@ -1,17 +1,31 @@
 package pom_component_parsing
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/liwenson/pom_component_parsing/model"
 )
 
 // Coordinate 表示 Maven 工件的坐标信息
+// Type 与 Classifier 为空时分别等同于 jar 与无分类器，用于区分同一 groupId:artifactId 下的不同工件，
+// 如 netty-transport-native-epoll:jar:linux-x86_64 与普通 jar、test-jar 与主 jar
 type Coordinate struct {
-	GroupId    string `json:"group_id"`    // 组ID
-	ArtifactId string `json:"artifact_id"` // 工件ID
-	Version    string `json:"version"`     // 版本
+	GroupId    string `json:"group_id"`             // 组ID
+	ArtifactId string `json:"artifact_id"`          // 工件ID
+	Version    string `json:"version"`              // 版本
+	Type       string `json:"type,omitempty"`       // 工件类型（打包类型），如 jar、pom、test-jar，为空表示 jar
+	Classifier string `json:"classifier,omitempty"` // 分类器，如 sources、linux-x86_64
 }
 
+// DefaultArtifactType 是 Maven 依赖的默认类型
+const DefaultArtifactType = "jar"
+
+// ErrInvalidCoordinate 表示坐标字符串的格式不正确
+var ErrInvalidCoordinate = errors.New("无效的坐标")
+
 // precompiledRegexp 是预编译的正则表达式，用于匹配所有空白字符
 var precompiledRegexp = regexp.MustCompile(`\s`)
 
@@ -21,15 +35,25 @@ func (c Coordinate) IsSnapshotVersion() bool {
 	return strings.HasSuffix(c.Version, "-SNAPSHOT")
 }
 
-// Normalize 返回一个新的 Coordinate，其中 GroupId、ArtifactId 和 Version 中的所有空白字符已被移除
+// Normalize 返回一个新的 Coordinate，其中所有字段中的空白字符已被移除
 func (c Coordinate) Normalize() Coordinate {
 	return Coordinate{
 		GroupId:    precompiledRegexp.ReplaceAllString(c.GroupId, ""),    // 去除 GroupId 前后空白字符
 		ArtifactId: precompiledRegexp.ReplaceAllString(c.ArtifactId, ""), // 去除 ArtifactId 前后空白字符
 		Version:    precompiledRegexp.ReplaceAllString(c.Version, ""),    // 去除 Version 前后空白字符
+		Type:       precompiledRegexp.ReplaceAllString(c.Type, ""),       // 去除 Type 前后空白字符
+		Classifier: precompiledRegexp.ReplaceAllString(c.Classifier, ""), // 去除 Classifier 前后空白字符
 	}
 }
 
+// ArtifactType 返回工件类型，未指定时返回默认的 jar
+func (c Coordinate) ArtifactType() string {
+	if t := c.Normalize().Type; t != "" {
+		return t
+	}
+	return DefaultArtifactType
+}
+
 // HasVersion 检查 Coordinate 是否包含有效的版本信息
 func (c Coordinate) HasVersion() bool {
 	normalized := c.Normalize()
@@ -42,29 +66,83 @@ func (c Coordinate) Name() string {
 	return normalized.GroupId + ":" + normalized.ArtifactId
 }
 
-// String 返回 Coordinate 的完整字符串表示，如果存在版本则包括版本信息
+// ManagementKey 返回与 Maven dependencyManagement 一致的依赖标识
+// 默认类型且没有分类器时为 "GroupId:ArtifactId"，否则为 "GroupId:ArtifactId:Type[:Classifier]"
+func (c Coordinate) ManagementKey() string {
+	normalized := c.Normalize()
+	if normalized.Classifier != "" {
+		return normalized.Name() + ":" + normalized.ArtifactType() + ":" + normalized.Classifier
+	}
+	if normalized.Type != "" && normalized.Type != DefaultArtifactType {
+		return normalized.Name() + ":" + normalized.Type
+	}
+	return normalized.Name()
+}
+
+// String 返回 Coordinate 的完整字符串表示，格式为 Maven 使用的 "GroupId:ArtifactId[:Type[:Classifier]]:Version"
+// 如果存在版本则包括版本信息，未指定 Type 且没有 Classifier 时省略 Type
 func (c Coordinate) String() string {
 	normalized := c.Normalize()
+	s := normalized.GroupId + ":" + normalized.ArtifactId
+	if normalized.Classifier != "" {
+		s += ":" + normalized.ArtifactType() + ":" + normalized.Classifier
+	} else if normalized.Type != "" {
+		s += ":" + normalized.Type
+	}
 	if normalized.Version == "" {
-		return normalized.GroupId + ":" + normalized.ArtifactId
+		return s
 	}
-	return normalized.GroupId + ":" + normalized.ArtifactId + ":" + normalized.Version
+	return s + ":" + normalized.Version
+}
+
+// ParseCoordinate 解析 Maven 使用的 "GroupId:ArtifactId[:Type[:Classifier]]:Version" 形式的坐标字符串
+// 也接受不带版本的 "GroupId:ArtifactId"
+func ParseCoordinate(s string) (Coordinate, error) {
+	parts := strings.Split(precompiledRegexp.ReplaceAllString(s, ""), ":")
+	for _, part := range parts {
+		if part == "" {
+			return Coordinate{}, fmt.Errorf("%w: %q 中存在空的字段", ErrInvalidCoordinate, s)
+		}
+	}
+
+	c := Coordinate{}
+	switch len(parts) {
+	case 2:
+		c.GroupId, c.ArtifactId = parts[0], parts[1]
+	case 3:
+		c.GroupId, c.ArtifactId, c.Version = parts[0], parts[1], parts[2]
+	case 4:
+		c.GroupId, c.ArtifactId, c.Type, c.Version = parts[0], parts[1], parts[2], parts[3]
+	case 5:
+		c.GroupId, c.ArtifactId, c.Type, c.Classifier, c.Version = parts[0], parts[1], parts[2], parts[3], parts[4]
+	default:
+		return Coordinate{}, fmt.Errorf("%w: %q 应为 GroupId:ArtifactId[:Type[:Classifier]]:Version 格式", ErrInvalidCoordinate, s)
+	}
+	return c, nil
 }
 
 // IsBad 判断 Coordinate 是否包含无效或格式错误的信息
 func (c Coordinate) IsBad() bool {
 	normalized := c.Normalize()
-	// 判断任一字段是否以 "${", "[", "(" 开头，通常表示变量未解析或格式不正确
+	// 判断任一字段是否以 "${" 开头，通常表示变量未解析
 	if strings.HasPrefix(normalized.GroupId, "${") ||
 		strings.HasPrefix(normalized.ArtifactId, "${") ||
-		strings.HasPrefix(normalized.Version, "${") ||
-		strings.HasPrefix(normalized.Version, "[") ||
-		strings.HasPrefix(normalized.Version, "(") {
+		strings.HasPrefix(normalized.Version, "${") {
 		return true
 	}
+	// 以 "[" 或 "(" 开头的版本是版本范围，只有语法不正确时才视为无效
+	if IsVersionRange(normalized.Version) {
+		_, err := ParseVersionRange(normalized.Version)
+		return err != nil
+	}
 	return false
 }
 
+// IsVersionRange 判断 Coordinate 的版本是否为范围形式，如 [1.2,2.0)
+func (c Coordinate) IsVersionRange() bool {
+	return IsVersionRange(c.Normalize().Version)
+}
+
 // Complete 检查 Coordinate 是否包含完整的 GroupId、ArtifactId 和 Version，且不包含格式错误的信息
 func (c Coordinate) Complete() bool {
 	normalized := c.Normalize()
@@ -77,6 +155,8 @@ func (c Coordinate) Complete() bool {
 
 // Compare 比较当前 Coordinate 与另一个 Coordinate 的顺序
 // 返回值遵循 strings.Compare 的约定：-1 表示小于，0 表示等于，1 表示大于
+// GroupId 与 ArtifactId 按字典序比较，Version 按 Maven ComparableVersion 的规则比较，
+// 以上均相同时再按 Type 与 Classifier 的字典序比较
 func (c Coordinate) Compare(other Coordinate) int {
 	// 先对当前 Coordinate 对象进行规范化处理，去除字段中的前后空白字符
 	cNormalized := c.Normalize()
@@ -96,6 +176,52 @@ func (c Coordinate) Compare(other Coordinate) int {
 		return cmp
 	}
 
-	// 如果 ArtifactId 也相同，则比较 Version 字段
-	return strings.Compare(cNormalized.Version, otherNormalized.Version)
+	// 如果 ArtifactId 也相同，则按 Maven 的规则比较 Version 字段，如 1.10.0 > 1.9.0、1.0-alpha < 1.0
+	if cmp := CompareVersions(cNormalized.Version, otherNormalized.Version); cmp != 0 {
+		return cmp
+	}
+
+	// 最后比较 Type 与 Classifier 字段，未指定的 Type 视为 jar
+	if cmp := strings.Compare(cNormalized.ArtifactType(), otherNormalized.ArtifactType()); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(cNormalized.Classifier, otherNormalized.Classifier)
+}
+
+// PackageURL 返回坐标对应的 purl，type（非 jar 时）与 classifier 作为限定符
+func (c Coordinate) PackageURL() model.PackageURL {
+	normalized := c.Normalize()
+	return model.Component{
+		CompName:       normalized.Name(),
+		CompVersion:    normalized.Version,
+		CompType:       normalized.Type,
+		CompClassifier: normalized.Classifier,
+		EcoRepo:        EcoRepo,
+	}.PackageURL()
+}
+
+// Purl 返回坐标对应的 purl 字符串，如 pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64
+func (c Coordinate) Purl() string {
+	return c.PackageURL().String()
+}
+
+// ParsePurl 将 Maven purl 解析为坐标，是 Coordinate.Purl 的逆操作，repository_url 等其他限定符会被忽略
+func ParsePurl(s string) (Coordinate, error) {
+	p, err := model.ParsePackageURL(s)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	if p.Type != model.PurlTypeMaven {
+		return Coordinate{}, fmt.Errorf("%w: %q 不是 Maven 的 purl", model.ErrInvalidPurl, s)
+	}
+	if p.Namespace == "" {
+		return Coordinate{}, fmt.Errorf("%w: %q 缺少 groupId", model.ErrInvalidPurl, s)
+	}
+	return Coordinate{
+		GroupId:    p.Namespace,
+		ArtifactId: p.Name,
+		Version:    p.Version,
+		Type:       p.Qualifiers["type"],
+		Classifier: p.Qualifiers["classifier"],
+	}, nil
 }
@@ -1,7 +1,10 @@
 package pom_component_parsing
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/liwenson/pom_component_parsing/model"
 )
 
 // TestCoordinate_IsSnapshotVersion 测试 Coordinate 的 IsSnapshotVersion 方法
@@ -234,6 +237,26 @@ func TestCoordinate_String(t *testing.T) {
 			},
 			expected: "com.example:example-artifact:1.0.0",
 		},
+		{
+			name: "包含类型",
+			coord: Coordinate{
+				GroupId:    "com.example",
+				ArtifactId: "example-artifact",
+				Version:    "1.0.0",
+				Type:       "test-jar",
+			},
+			expected: "com.example:example-artifact:test-jar:1.0.0",
+		},
+		{
+			name: "包含分类器时补全默认类型",
+			coord: Coordinate{
+				GroupId:    "io.netty",
+				ArtifactId: "netty-transport-native-epoll",
+				Version:    "4.1.100.Final",
+				Classifier: "linux-x86_64",
+			},
+			expected: "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+		},
 	}
 
 	for _, tt := range tests {
@@ -290,12 +313,21 @@ func TestCoordinate_IsBad(t *testing.T) {
 			expected: true,
 		},
 		{
-			name: "Version以[开头",
+			name: "Version为合法的版本范围",
 			coord: Coordinate{
 				GroupId:    "com.example",
 				ArtifactId: "example-artifact",
 				Version:    "[1.0.0]",
 			},
+			expected: false,
+		},
+		{
+			name: "Version为不合法的版本范围",
+			coord: Coordinate{
+				GroupId:    "com.example",
+				ArtifactId: "example-artifact",
+				Version:    "[2.0,1.0)",
+			},
 			expected: true,
 		},
 		{
@@ -501,6 +533,34 @@ func TestCoordinate_Compare(t *testing.T) {
 			},
 			expected: -1,
 		},
+		{
+			name: "Version按数字比较",
+			coord: Coordinate{
+				GroupId:    "com.example",
+				ArtifactId: "artifact",
+				Version:    "1.10.0",
+			},
+			other: Coordinate{
+				GroupId:    "com.example",
+				ArtifactId: "artifact",
+				Version:    "1.9.0",
+			},
+			expected: 1,
+		},
+		{
+			name: "预发布版本小于正式版本",
+			coord: Coordinate{
+				GroupId:    "com.example",
+				ArtifactId: "artifact",
+				Version:    "1.0-alpha",
+			},
+			other: Coordinate{
+				GroupId:    "com.example",
+				ArtifactId: "artifact",
+				Version:    "1.0",
+			},
+			expected: -1,
+		},
 		{
 			name: "GroupId更大",
 			coord: Coordinate{
@@ -596,3 +656,119 @@ func TestCoordinate_Compare(t *testing.T) {
 		})
 	}
 }
+
+// TestParseCoordinate 测试 ParseCoordinate 对各种坐标字符串形式的解析
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected Coordinate
+		wantErr  bool
+	}{
+		{
+			name:     "不包含版本",
+			s:        "com.example:example-artifact",
+			expected: Coordinate{GroupId: "com.example", ArtifactId: "example-artifact"},
+		},
+		{
+			name:     "包含版本",
+			s:        "com.example:example-artifact:1.0.0",
+			expected: Coordinate{GroupId: "com.example", ArtifactId: "example-artifact", Version: "1.0.0"},
+		},
+		{
+			name:     "包含类型",
+			s:        "com.example:example-artifact:test-jar:1.0.0",
+			expected: Coordinate{GroupId: "com.example", ArtifactId: "example-artifact", Version: "1.0.0", Type: "test-jar"},
+		},
+		{
+			name:     "包含类型与分类器",
+			s:        "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+			expected: Coordinate{GroupId: "io.netty", ArtifactId: "netty-transport-native-epoll", Version: "4.1.100.Final", Type: "jar", Classifier: "linux-x86_64"},
+		},
+		{name: "字段过少", s: "com.example", wantErr: true},
+		{name: "字段过多", s: "a:b:c:d:e:f", wantErr: true},
+		{name: "存在空字段", s: "com.example::1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseCoordinate(tt.s)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCoordinate) {
+					t.Errorf("ParseCoordinate(%q) error = %v, want ErrInvalidCoordinate", tt.s, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCoordinate(%q) error = %v", tt.s, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseCoordinate(%q) = %#v, want %#v", tt.s, result, tt.expected)
+			}
+			if result.String() != tt.s {
+				t.Errorf("String() = %s, want %s", result.String(), tt.s)
+			}
+		})
+	}
+}
+
+// TestCoordinate_ManagementKey 测试 Coordinate 的 ManagementKey 方法
+func TestCoordinate_ManagementKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		coord    Coordinate
+		expected string
+	}{
+		{name: "默认类型", coord: Coordinate{GroupId: "g", ArtifactId: "a", Version: "1"}, expected: "g:a"},
+		{name: "显式声明 jar 类型", coord: Coordinate{GroupId: "g", ArtifactId: "a", Type: "jar"}, expected: "g:a"},
+		{name: "非默认类型", coord: Coordinate{GroupId: "g", ArtifactId: "a", Type: "test-jar"}, expected: "g:a:test-jar"},
+		{name: "包含分类器", coord: Coordinate{GroupId: "g", ArtifactId: "a", Classifier: "linux-x86_64"}, expected: "g:a:jar:linux-x86_64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.coord.ManagementKey(); result != tt.expected {
+				t.Errorf("ManagementKey() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCoordinate_Purl 测试 Coordinate 与 purl 之间的相互转换
+func TestCoordinate_Purl(t *testing.T) {
+	tests := []struct {
+		name     string
+		coord    Coordinate
+		expected string
+	}{
+		{
+			name:     "默认类型",
+			coord:    Coordinate{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "2.0.9"},
+			expected: "pkg:maven/org.slf4j/slf4j-api@2.0.9",
+		},
+		{
+			name:     "包含类型与分类器",
+			coord:    Coordinate{GroupId: "com.example", ArtifactId: "core", Version: "1.0.0", Type: "test-jar", Classifier: "tests"},
+			expected: "pkg:maven/com.example/core@1.0.0?classifier=tests&type=test-jar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.coord.Purl(); result != tt.expected {
+				t.Errorf("Purl() = %s, want %s", result, tt.expected)
+			}
+			parsed, err := ParsePurl(tt.expected)
+			if err != nil {
+				t.Fatalf("ParsePurl(%q) error = %v", tt.expected, err)
+			}
+			if parsed != tt.coord {
+				t.Errorf("ParsePurl(%q) = %#v, want %#v", tt.expected, parsed, tt.coord)
+			}
+		})
+	}
+
+	if _, err := ParsePurl("pkg:npm/lodash@4.17.21"); !errors.Is(err, model.ErrInvalidPurl) {
+		t.Errorf("ParsePurl() 对非 Maven 的 purl 应返回 ErrInvalidPurl, got %v", err)
+	}
+}
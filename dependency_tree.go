@@ -0,0 +1,278 @@
+package pom_component_parsing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidDependencyTree 表示 dependency:tree 的输出无法解析
+var ErrInvalidDependencyTree = errors.New("invalid dependency tree")
+
+// ReadDependencyTreeFile 读取并解析 dependency:tree 的结果文件
+func ReadDependencyTreeFile(path string, outputType DependencyTreeOutputType) (*Dependency, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件 %s 失败: %w", path, err)
+	}
+	defer f.Close()
+
+	tree, err := ParseDependencyTree(f, outputType)
+	if err != nil {
+		return nil, fmt.Errorf("解析文件 %s 失败: %w", path, err)
+	}
+	return tree, nil
+}
+
+// ParseDependencyTree 解析单个模块的 dependency:tree 输出，返回以模块自身为根的依赖树
+func ParseDependencyTree(r io.Reader, outputType DependencyTreeOutputType) (*Dependency, error) {
+	switch outputType {
+	case DependencyTreeText, "":
+		return parseTextDependencyTree(r)
+	case DependencyTreeTGF:
+		return parseTGFDependencyTree(r)
+	case DependencyTreeDOT:
+		return parseDOTDependencyTree(r)
+	default:
+		return nil, fmt.Errorf("%w: 不支持的输出格式 %s", ErrInvalidDependencyTree, outputType)
+	}
+}
+
+// parseTreeArtifact 解析 Maven 输出的工件字符串 groupId:artifactId:type[:classifier]:version[:scope]，
+// 根节点（模块自身）没有作用域
+func parseTreeArtifact(s string, root bool) (Dependency, error) {
+	// 去掉 verbose 模式下的附加说明，如 "(optional)"、"(version managed from 1.0)"
+	s, _, _ = strings.Cut(strings.TrimSpace(s), " ")
+	parts := strings.Split(s, ":")
+
+	var scope string
+	if !root {
+		if len(parts) < 5 {
+			return Dependency{}, fmt.Errorf("%w: 工件 %q 缺少作用域", ErrInvalidDependencyTree, s)
+		}
+		scope, parts = parts[len(parts)-1], parts[:len(parts)-1]
+	}
+
+	var c Coordinate
+	switch len(parts) {
+	case 4:
+		c = Coordinate{GroupId: parts[0], ArtifactId: parts[1], Type: parts[2], Version: parts[3]}
+	case 5:
+		c = Coordinate{GroupId: parts[0], ArtifactId: parts[1], Type: parts[2], Classifier: parts[3], Version: parts[4]}
+	default:
+		return Dependency{}, fmt.Errorf("%w: 无法解析工件 %q", ErrInvalidDependencyTree, s)
+	}
+	return Dependency{Coordinate: c, Scope: scope}, nil
+}
+
+// treeEntry 表示文本树中的一行
+type treeEntry struct {
+	depth int
+	dep   Dependency
+}
+
+// parseTextDependencyTree 解析 -DoutputType=text 的输出，每层缩进为 3 个字符（"+- "、"\- "、"|  " 或空格），
+// verbose 模式下被省略的依赖（以括号包围）会被忽略
+func parseTextDependencyTree(r io.Reader) (*Dependency, error) {
+	var entries []treeEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimRight(scanner.Text(), "\r "), "[INFO] ")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		artifact := strings.TrimLeft(line, "|+-\\ ")
+		prefix := len(line) - len(artifact)
+		if prefix%3 != 0 {
+			return nil, fmt.Errorf("%w: 缩进不正确 %q", ErrInvalidDependencyTree, line)
+		}
+		depth := prefix / 3
+		if len(entries) == 0 && depth != 0 {
+			return nil, fmt.Errorf("%w: 缺少根节点", ErrInvalidDependencyTree)
+		}
+		if len(entries) > 0 && depth == 0 {
+			// 结果文件只包含一个模块，之后的内容忽略
+			break
+		}
+		if len(entries) > 0 && depth > entries[len(entries)-1].depth+1 {
+			return nil, fmt.Errorf("%w: 缩进不正确 %q", ErrInvalidDependencyTree, line)
+		}
+		if strings.HasPrefix(artifact, "(") {
+			// 被省略的依赖不会出现在解析结果中，但其缩进仍需校验
+			entries = append(entries, treeEntry{depth: depth})
+			continue
+		}
+
+		dep, err := parseTreeArtifact(artifact, depth == 0)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, treeEntry{depth: depth, dep: dep})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取依赖树失败: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: 内容为空", ErrInvalidDependencyTree)
+	}
+
+	root := entries[0].dep
+	i := 1
+	root.Children = buildTreeChildren(entries, &i, 1)
+	return &root, nil
+}
+
+// buildTreeChildren 从 entries[*i] 开始收集深度为 depth 的连续节点及其子节点
+func buildTreeChildren(entries []treeEntry, i *int, depth int) []Dependency {
+	var children []Dependency
+	for *i < len(entries) && entries[*i].depth == depth {
+		entry := entries[*i]
+		*i++
+		entry.dep.Children = buildTreeChildren(entries, i, depth+1)
+		if !entry.dep.IsZero() {
+			children = append(children, entry.dep)
+		}
+	}
+	return children
+}
+
+// parseTGFDependencyTree 解析 -DoutputType=tgf 的输出：先是 "id 工件" 形式的节点，
+// 然后是单独一行的 "#"，最后是 "from to scope" 形式的边，第一个节点为根节点
+func parseTGFDependencyTree(r io.Reader) (*Dependency, error) {
+	g := newTreeGraph()
+	edgeSection := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "#" {
+			edgeSection = true
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w: 无法解析 %q", ErrInvalidDependencyTree, line)
+		}
+		if edgeSection {
+			g.addEdge(fields[0], fields[1])
+		} else {
+			g.addNode(fields[0], strings.Join(fields[1:], " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取依赖树失败: %w", err)
+	}
+	if len(g.order) == 0 {
+		return nil, fmt.Errorf("%w: 内容为空", ErrInvalidDependencyTree)
+	}
+	return g.tree(g.order[0])
+}
+
+var (
+	// dotDigraphPattern 匹配 DOT 输出的第一行 digraph "groupId:artifactId:type:version" {
+	dotDigraphPattern = regexp.MustCompile(`^\s*digraph\s+"([^"]+)"`)
+	// dotEdgePattern 匹配 DOT 输出中的边 "from" -> "to" ;
+	dotEdgePattern = regexp.MustCompile(`"([^"]+)"\s*->\s*"([^"]+)"`)
+)
+
+// parseDOTDependencyTree 解析 -DoutputType=dot 的输出，节点以工件字符串标识，digraph 的名称为根节点
+func parseDOTDependencyTree(r io.Reader) (*Dependency, error) {
+	g := newTreeGraph()
+	var root string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if root == "" {
+			if m := dotDigraphPattern.FindStringSubmatch(line); m != nil {
+				root = m[1]
+				g.addNode(root, root)
+			}
+			continue
+		}
+		if m := dotEdgePattern.FindStringSubmatch(line); m != nil {
+			g.addNode(m[1], m[1])
+			g.addNode(m[2], m[2])
+			g.addEdge(m[1], m[2])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取依赖树失败: %w", err)
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%w: 缺少 digraph 声明", ErrInvalidDependencyTree)
+	}
+	return g.tree(root)
+}
+
+// treeGraph 是 tgf 与 dot 输出对应的有向图，节点以 id 标识，label 为工件字符串
+type treeGraph struct {
+	labels map[string]string
+	edges  map[string][]string
+	order  []string // 节点首次出现的顺序
+}
+
+// newTreeGraph 创建空的 treeGraph
+func newTreeGraph() *treeGraph {
+	return &treeGraph{labels: map[string]string{}, edges: map[string][]string{}}
+}
+
+// addNode 加入节点，已存在的节点保持不变
+func (g *treeGraph) addNode(id, label string) {
+	if _, ok := g.labels[id]; ok {
+		return
+	}
+	g.labels[id] = label
+	g.order = append(g.order, id)
+}
+
+// addEdge 加入 from 依赖 to 的边
+func (g *treeGraph) addEdge(from, to string) {
+	g.edges[from] = append(g.edges[from], to)
+}
+
+// tree 从根节点展开为依赖树，遇到循环依赖时跳过形成环的边
+func (g *treeGraph) tree(rootID string) (*Dependency, error) {
+	visiting := map[string]bool{}
+	var build func(id string, root bool) (Dependency, error)
+	build = func(id string, root bool) (Dependency, error) {
+		label, ok := g.labels[id]
+		if !ok {
+			return Dependency{}, fmt.Errorf("%w: 未定义的节点 %s", ErrInvalidDependencyTree, id)
+		}
+		dep, err := parseTreeArtifact(label, root)
+		if err != nil {
+			return Dependency{}, err
+		}
+
+		visiting[id] = true
+		defer delete(visiting, id)
+		for _, to := range g.edges[id] {
+			if visiting[to] {
+				continue
+			}
+			child, err := build(to, false)
+			if err != nil {
+				return Dependency{}, err
+			}
+			dep.Children = append(dep.Children, child)
+		}
+		return dep, nil
+	}
+
+	root, err := build(rootID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
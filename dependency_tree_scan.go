@@ -0,0 +1,80 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ScanDepsByDependencyTreeCommand 使用 maven-dependency-plugin:tree 扫描依赖关系，
+// 适用于 depgraph 插件无法下载（如被私服策略拦截）的环境。
+// 与 ScanDepsByPluginCommand 一样不传递 -P 参数，由 Maven 在每个模块中分别评估 profile 的激活条件。
+func ScanDepsByDependencyTreeCommand(projectDir string, mvnCmdInfo *MvnCommandInfo, outputType DependencyTreeOutputType) (*DepsMap, error) {
+	return ScanDepsByDependencyTreeCommandWithProfiles(projectDir, mvnCmdInfo, nil, outputType)
+}
+
+// ScanDepsByDependencyTreeCommandWithProfiles 使用显式指定的 profile 集合执行 dependency:tree 扫描依赖关系。
+// profiles 为空时不传递 -P 参数，由 Maven 自行评估激活条件。
+func ScanDepsByDependencyTreeCommandWithProfiles(projectDir string, mvnCmdInfo *MvnCommandInfo, profiles []string, outputType DependencyTreeOutputType) (*DepsMap, error) {
+	return scanDepsByDependencyTreeCmd(context.Background(), DependencyTreeCmd{
+		MavenCmdInfo: mvnCmdInfo,
+		Profiles:     profiles,
+		Timeout:      DefaultMvnTimeout,
+		ScanDir:      projectDir,
+		OutputType:   outputType,
+	})
+}
+
+// scanDepsByDependencyTreeCmd 执行配置好的 dependency:tree 命令并收集结果文件
+func scanDepsByDependencyTreeCmd(ctx context.Context, c DependencyTreeCmd) (*DepsMap, error) {
+	if c.OutputType == "" {
+		c.OutputType = DependencyTreeText
+	}
+	logger := loggerOrNop(c.Logger)
+
+	// 执行 dependency:tree 命令
+	if err := c.RunContext(ctx); err != nil {
+		var buildErr *MvnBuildError
+		if c.AllowPartial && errors.As(err, &buildErr) {
+			logger.Warn("部分模块构建失败，继续收集其余模块的结果文件", zap.Error(err))
+			deps, err := collectDependencyTreeFiles(ctx, logger, c.Progress, c.ScanDir, c.OutputType)
+			return partialBuildResult(buildErr, deps, err)
+		}
+		return nil, err
+	}
+
+	return collectDependencyTreeFiles(ctx, logger, c.Progress, c.ScanDir, c.OutputType)
+}
+
+// collectDependencyTreeFiles 收集项目目录中各模块的 dependency:tree 结果文件并解析依赖关系，每个文件解析完成后发出 graph-file-parsed 事件。
+// 无法解析的文件不影响其余模块，返回的 *PartialResultError 中记录这些模块的 *GraphParseError
+func collectDependencyTreeFiles(ctx context.Context, logger *zap.Logger, progress ProgressFunc, projectDir string, outputType DependencyTreeOutputType) (*DepsMap, error) {
+	paths, err := findResultFiles(ctx, logger, projectDir, outputType.FileName())
+	if err != nil {
+		return nil, err
+	}
+
+	rs := newDepsMap()
+	var errs []ModuleError
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		module := resultFileModulePath(logger, projectDir, path)
+		tree, err := ReadDependencyTreeFile(path, outputType)
+		if err != nil {
+			// 打印解析文件时的错误信息，并继续处理下一个文件
+			logger.Warn("解析依赖树文件时出错", zap.String("module", module), zap.String("path", path), zap.Error(err))
+			errs = append(errs, ModuleError{Module: module, Err: &GraphParseError{Path: path, Err: err}})
+			progress.emit(ProgressEvent{Phase: ProgressGraphFileParsed, Duration: time.Since(start), Module: module, Index: i + 1, Total: len(paths), Path: path, Err: err})
+			continue
+		}
+		rs.put(tree.Coordinate, tree.Children, module)
+		logger.Debug("解析依赖树文件完成", zap.String("module", module), zap.String("path", path), zap.Duration("duration", time.Since(start)))
+		progress.emit(ProgressEvent{Phase: ProgressGraphFileParsed, Duration: time.Since(start), Module: module, Index: i + 1, Total: len(paths), Path: path})
+	}
+	return rs, partialResult(errs)
+}
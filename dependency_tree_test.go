@@ -0,0 +1,153 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// wantParsedTree 是下列各格式的 dependency:tree 输出对应的依赖树
+func wantParsedTree() *Dependency {
+	return &Dependency{
+		Coordinate: Coordinate{GroupId: "com.example", ArtifactId: "app", Type: "jar", Version: "1.0.0"},
+		Children: []Dependency{
+			{
+				Coordinate: Coordinate{GroupId: "org.springframework", ArtifactId: "spring-web", Type: "jar", Version: "5.3.30"},
+				Scope:      "compile",
+				Children: []Dependency{
+					{Coordinate: Coordinate{GroupId: "org.springframework", ArtifactId: "spring-core", Type: "jar", Version: "5.3.30"}, Scope: "compile"},
+				},
+			},
+			{
+				Coordinate: Coordinate{GroupId: "io.netty", ArtifactId: "netty-transport-native-epoll", Type: "jar", Classifier: "linux-x86_64", Version: "4.1.100.Final"},
+				Scope:      "provided",
+			},
+		},
+	}
+}
+
+func TestParseDependencyTree(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputType DependencyTreeOutputType
+		input      string
+	}{
+		{
+			name:       "text格式",
+			outputType: DependencyTreeText,
+			input: `com.example:app:jar:1.0.0
++- org.springframework:spring-web:jar:5.3.30:compile
+|  \- org.springframework:spring-core:jar:5.3.30:compile
+\- io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:provided
+`,
+		},
+		{
+			name:       "text格式的verbose输出与日志前缀",
+			outputType: DependencyTreeText,
+			input: `[INFO] com.example:app:jar:1.0.0
+[INFO] +- org.springframework:spring-web:jar:5.3.30:compile
+[INFO] |  +- org.springframework:spring-core:jar:5.3.30:compile
+[INFO] |  \- (org.springframework:spring-jcl:jar:5.3.30:compile - omitted for duplicate)
+[INFO] \- io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:provided (optional)
+`,
+		},
+		{
+			name:       "tgf格式",
+			outputType: DependencyTreeTGF,
+			input: `1001 com.example:app:jar:1.0.0
+1002 org.springframework:spring-web:jar:5.3.30:compile
+1003 org.springframework:spring-core:jar:5.3.30:compile
+1004 io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:provided
+#
+1001 1002 compile
+1002 1003 compile
+1001 1004 provided
+`,
+		},
+		{
+			name:       "dot格式",
+			outputType: DependencyTreeDOT,
+			input: `digraph "com.example:app:jar:1.0.0" {
+	"com.example:app:jar:1.0.0" -> "org.springframework:spring-web:jar:5.3.30:compile" ;
+	"com.example:app:jar:1.0.0" -> "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:provided" ;
+	"org.springframework:spring-web:jar:5.3.30:compile" -> "org.springframework:spring-core:jar:5.3.30:compile" ;
+ }
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDependencyTree(strings.NewReader(tt.input), tt.outputType)
+			if err != nil {
+				t.Fatalf("ParseDependencyTree() error = %v", err)
+			}
+			if want := wantParsedTree(); !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseDependencyTree() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseDependencyTree_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputType DependencyTreeOutputType
+		input      string
+	}{
+		{name: "空内容", outputType: DependencyTreeText, input: ""},
+		{name: "缺少根节点", outputType: DependencyTreeText, input: "+- a:b:jar:1.0:compile\n"},
+		{name: "缩进跳级", outputType: DependencyTreeText, input: "a:b:jar:1.0\n|  \\- c:d:jar:1.0:compile\n"},
+		{name: "缺少作用域", outputType: DependencyTreeText, input: "a:b:jar:1.0\n\\- c:d:1.0\n"},
+		{name: "tgf边引用未定义的节点", outputType: DependencyTreeTGF, input: "1 a:b:jar:1.0\n#\n1 2 compile\n"},
+		{name: "dot缺少digraph", outputType: DependencyTreeDOT, input: `"a:b:jar:1.0" -> "c:d:jar:1.0:compile"`},
+		{name: "不支持的格式", outputType: "graphml", input: "a:b:jar:1.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDependencyTree(strings.NewReader(tt.input), tt.outputType)
+			if !errors.Is(err, ErrInvalidDependencyTree) {
+				t.Errorf("ParseDependencyTree() error = %v, want %v", err, ErrInvalidDependencyTree)
+			}
+		})
+	}
+}
+
+func TestCollectDependencyTreeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"target/dependency-tree.txt":        "com.example:parent:pom:1.0.0\n",
+		"app/target/dependency-tree.txt":    "com.example:app:jar:1.0.0\n\\- org.slf4j:slf4j-api:jar:2.0.9:compile\n",
+		"broken/target/dependency-tree.txt": "not a tree\n",
+	}
+	for name, content := range files {
+		writeTestFile(t, dir, name, content)
+	}
+
+	// 无法解析的文件不影响其余模块，记录在 *PartialResultError 中
+	deps, err := collectDependencyTreeFiles(context.Background(), zaptest.NewLogger(t), nil, dir, DependencyTreeText)
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || !errors.Is(err, ErrGraphParse) {
+		t.Fatalf("collectDependencyTreeFiles() error = %v, want *PartialResultError", err)
+	}
+	if len(partial.Modules) != 1 || partial.Modules[0].Module != filepath.Join("broken", "pom.xml") {
+		t.Errorf("Modules = %v", partial.Modules)
+	}
+	if deps.Size() != 2 {
+		t.Fatalf("Size() = %d, want 2", deps.Size())
+	}
+
+	app, ok := deps.Get(Coordinate{GroupId: "com.example", ArtifactId: "app", Type: "jar", Version: "1.0.0"})
+	if !ok {
+		t.Fatalf("缺少模块 com.example:app")
+	}
+	if app.relativePath != filepath.Join("app", "pom.xml") || len(app.children) != 1 {
+		t.Errorf("模块 app = %+v", app)
+	}
+}
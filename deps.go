@@ -2,6 +2,7 @@ package pom_component_parsing
 
 import (
 	"sort"
+	"strings"
 )
 
 // DepsMap 依赖关系映射结构
@@ -32,9 +33,12 @@ func (d *DepsMap) ListAllEntries() []depsElement {
 	}
 
 	// 根据坐标对结果进行排序
-	// 使用Coordinate的Compare方法作为排序依据
+	// 使用Coordinate的Compare方法作为排序依据，按 Maven 规则相等的版本（如 1.0 与 1.0.0）再按字符串排序
 	sort.Slice(rs, func(i, j int) bool {
-		return rs[i].coordinate.Compare(rs[j].coordinate) < 0
+		if cmp := rs[i].coordinate.Compare(rs[j].coordinate); cmp != 0 {
+			return cmp < 0
+		}
+		return rs[i].coordinate.String() < rs[j].coordinate.String()
 	})
 
 	return rs
@@ -99,3 +103,103 @@ func (d *DepsMap) HasDependency(coordinate Coordinate) bool {
 	_, exists := d.m[coordinate]
 	return exists
 }
+
+// DepsDiff 两次扫描结果之间的依赖差异
+type DepsDiff struct {
+	Added   []Coordinate    // 只在新结果中出现的依赖
+	Removed []Coordinate    // 只在旧结果中出现的依赖
+	Changed []VersionChange // 两次结果中都出现但版本不同的依赖
+}
+
+// VersionChange 表示同一个依赖在两次扫描结果中的版本变化
+type VersionChange struct {
+	Name string // 依赖名称，格式与 Coordinate.ManagementKey 相同
+	From string // 旧结果中的版本，多个版本以逗号分隔
+	To   string // 新结果中的版本，多个版本以逗号分隔
+}
+
+// IsEmpty 判断两次结果是否没有差异
+func (d DepsDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffDepsMap 比较两次扫描得到的依赖关系（包括所有传递依赖），常用于比较不同 profile 组合的扫描结果
+func DiffDepsMap(base, other *DepsMap) DepsDiff {
+	baseVersions, otherVersions := base.versionsByName(), other.versionsByName()
+
+	var diff DepsDiff
+	for name, versions := range otherVersions {
+		baseVers, ok := baseVersions[name]
+		if !ok {
+			for _, v := range versions {
+				diff.Added = append(diff.Added, coordinateOfName(name, v))
+			}
+			continue
+		}
+		from, to := strings.Join(baseVers, ","), strings.Join(versions, ",")
+		if from != to {
+			diff.Changed = append(diff.Changed, VersionChange{Name: name, From: from, To: to})
+		}
+	}
+	for name, versions := range baseVersions {
+		if _, ok := otherVersions[name]; !ok {
+			for _, v := range versions {
+				diff.Removed = append(diff.Removed, coordinateOfName(name, v))
+			}
+		}
+	}
+
+	sortCoordinates(diff.Added)
+	sortCoordinates(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Name < diff.Changed[j].Name
+	})
+	return diff
+}
+
+// versionsByName 收集所有依赖（包括传递依赖）的版本，键为 Coordinate.ManagementKey，值为排序去重后的版本列表
+func (d *DepsMap) versionsByName() map[string][]string {
+	seen := map[string]map[string]struct{}{}
+	var walk func(deps []Dependency)
+	walk = func(deps []Dependency) {
+		for _, dep := range deps {
+			key := dep.ManagementKey()
+			if seen[key] == nil {
+				seen[key] = map[string]struct{}{}
+			}
+			seen[key][dep.Version] = struct{}{}
+			walk(dep.Children)
+		}
+	}
+	if d != nil {
+		for _, it := range d.m {
+			walk(it.children)
+		}
+	}
+
+	rs := make(map[string][]string, len(seen))
+	for name, versions := range seen {
+		for v := range versions {
+			rs[name] = append(rs[name], v)
+		}
+		SortVersions(rs[name])
+	}
+	return rs
+}
+
+// coordinateOfName 根据 Coordinate.ManagementKey 形式的名称与版本构造坐标
+func coordinateOfName(name, version string) Coordinate {
+	c, err := ParseCoordinate(name + ":" + version)
+	if err != nil {
+		groupId, artifactId, _ := strings.Cut(name, ":")
+		return Coordinate{GroupId: groupId, ArtifactId: artifactId, Version: version}
+	}
+	return c
+}
+
+// sortCoordinates 按坐标顺序对切片排序
+func sortCoordinates(cs []Coordinate) {
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].Compare(cs[j]) < 0
+	})
+}
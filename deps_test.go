@@ -0,0 +1,39 @@
+package pom_component_parsing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDepsMap(t *testing.T) {
+	module := Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"}
+	dep := func(g, a, v string, children ...Dependency) Dependency {
+		return Dependency{Coordinate: Coordinate{GroupId: g, ArtifactId: a, Version: v}, Children: children}
+	}
+
+	base := newDepsMap()
+	base.put(module, []Dependency{
+		dep("com.google.guava", "guava", "31.0-jre", dep("com.google.code.findbugs", "jsr305", "3.0.2")),
+		dep("javax.xml.bind", "jaxb-api", "2.3.1"),
+	}, "pom.xml")
+
+	other := newDepsMap()
+	other.put(module, []Dependency{
+		dep("com.google.guava", "guava", "32.1.2-jre", dep("com.google.code.findbugs", "jsr305", "3.0.2")),
+		dep("jakarta.xml.bind", "jakarta.xml.bind-api", "4.0.0"),
+	}, "pom.xml")
+
+	diff := DiffDepsMap(base, other)
+	want := DepsDiff{
+		Added:   []Coordinate{{GroupId: "jakarta.xml.bind", ArtifactId: "jakarta.xml.bind-api", Version: "4.0.0"}},
+		Removed: []Coordinate{{GroupId: "javax.xml.bind", ArtifactId: "jaxb-api", Version: "2.3.1"}},
+		Changed: []VersionChange{{Name: "com.google.guava:guava", From: "31.0-jre", To: "32.1.2-jre"}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffDepsMap() = %+v, want %+v", diff, want)
+	}
+
+	if !DiffDepsMap(base, base).IsEmpty() {
+		t.Errorf("相同结果的差异应为空")
+	}
+}
@@ -0,0 +1,282 @@
+package pom_component_parsing
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GraphFormat 表示依赖图的输出格式
+type GraphFormat string
+
+const (
+	GraphFormatDOT     GraphFormat = "dot"     // Graphviz DOT
+	GraphFormatMermaid GraphFormat = "mermaid" // Mermaid flowchart
+	GraphFormatGraphML GraphFormat = "graphml" // GraphML
+)
+
+// GraphOptions 控制依赖图的生成
+type GraphOptions struct {
+	CollapseGroupId bool     // 是否将同一 groupId 的工件合并为一个节点
+	Scopes          []string // 只保留这些作用域的依赖（根节点总是保留），为空表示不过滤
+	MaxDepth        int      // 最大深度，根节点的直接依赖深度为 1，0 表示不限制
+	Highlight       string   // 需要高亮的目标工件，格式为 groupId:artifactId 或完整坐标，高亮从根节点到它的所有路径
+}
+
+// dependencyGraph 是从依赖树展开得到的有向图，节点与边按首次出现的顺序排列
+type dependencyGraph struct {
+	nodes []graphNode
+	edges []graphEdge
+	index map[string]int // 节点 key 到 nodes 下标的映射
+}
+
+// graphNode 表示图中的一个节点
+type graphNode struct {
+	id          string
+	label       string
+	highlighted bool
+}
+
+// graphEdge 表示图中的一条依赖边
+type graphEdge struct {
+	from, to    int
+	scope       string
+	highlighted bool
+}
+
+// RenderGraph 按指定格式输出以 root 为根的依赖图
+func RenderGraph(w io.Writer, root Dependency, format GraphFormat, opts GraphOptions) error {
+	g := buildDependencyGraph(root, opts)
+	switch format {
+	case GraphFormatDOT:
+		return g.writeDOT(w, root.Coordinate.String())
+	case GraphFormatMermaid:
+		return g.writeMermaid(w)
+	case GraphFormatGraphML:
+		return g.writeGraphML(w)
+	default:
+		return fmt.Errorf("不支持的依赖图格式: %s", format)
+	}
+}
+
+// buildDependencyGraph 按选项展开依赖树，并标记通往高亮目标的所有路径
+func buildDependencyGraph(root Dependency, opts GraphOptions) *dependencyGraph {
+	g := &dependencyGraph{index: map[string]int{}}
+	scopes := map[string]bool{}
+	for _, scope := range opts.Scopes {
+		scopes[scope] = true
+	}
+	edgeIndex := map[[2]int]int{}
+
+	// walk 返回以 dep 为根的子树中是否包含高亮目标
+	var walk func(dep Dependency, depth int) (int, bool)
+	walk = func(dep Dependency, depth int) (int, bool) {
+		node := g.node(dep.Coordinate, opts.CollapseGroupId)
+		found := opts.Highlight != "" && matchGraphTarget(dep.Coordinate, opts.Highlight, opts.CollapseGroupId)
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			g.nodes[node].highlighted = g.nodes[node].highlighted || found
+			return node, found
+		}
+
+		for _, child := range dep.Children {
+			if len(scopes) > 0 && !scopes[child.Scope] {
+				continue
+			}
+			to, childFound := walk(child, depth+1)
+			if to == node {
+				// 合并 groupId 后同组内部的依赖不再显示
+				found = found || childFound
+				continue
+			}
+			key := [2]int{node, to}
+			idx, ok := edgeIndex[key]
+			if !ok {
+				idx = len(g.edges)
+				edgeIndex[key] = idx
+				g.edges = append(g.edges, graphEdge{from: node, to: to, scope: child.Scope})
+			}
+			if childFound {
+				g.edges[idx].highlighted = true
+				found = true
+			}
+		}
+		g.nodes[node].highlighted = g.nodes[node].highlighted || found
+		return node, found
+	}
+	walk(root, 0)
+	return g
+}
+
+// node 返回坐标对应节点的下标，节点不存在时创建
+func (g *dependencyGraph) node(c Coordinate, collapse bool) int {
+	key, label := c.String(), c.String()
+	if collapse {
+		key, label = c.Normalize().GroupId, c.Normalize().GroupId
+	}
+	if idx, ok := g.index[key]; ok {
+		return idx
+	}
+	idx := len(g.nodes)
+	g.index[key] = idx
+	g.nodes = append(g.nodes, graphNode{id: "n" + strconv.Itoa(idx), label: label})
+	return idx
+}
+
+// matchGraphTarget 判断坐标是否为高亮目标，合并 groupId 时只比较 groupId
+func matchGraphTarget(c Coordinate, target string, collapse bool) bool {
+	target = precompiledRegexp.ReplaceAllString(target, "")
+	if collapse {
+		groupId, _, _ := strings.Cut(target, ":")
+		return c.Normalize().GroupId == groupId
+	}
+	return target == c.Name() || target == c.ManagementKey() || target == c.String()
+}
+
+// writeDOT 输出 Graphviz DOT 格式，高亮的节点与边以红色粗线显示
+func (g *dependencyGraph) writeDOT(w io.Writer, name string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n", strconv.Quote(name))
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&sb, "  %s [label=%s", n.id, strconv.Quote(n.label))
+		if n.highlighted {
+			sb.WriteString(`, color="red", penwidth=2`)
+		}
+		sb.WriteString("];\n")
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&sb, "  %s -> %s", g.nodes[e.from].id, g.nodes[e.to].id)
+		var attrs []string
+		if e.scope != "" {
+			attrs = append(attrs, "label="+strconv.Quote(e.scope))
+		}
+		if e.highlighted {
+			attrs = append(attrs, `color="red"`, "penwidth=2")
+		}
+		if len(attrs) > 0 {
+			sb.WriteString(" [" + strings.Join(attrs, ", ") + "]")
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeMermaid 输出 Mermaid flowchart 格式，高亮的节点与边使用 highlight 样式
+func (g *dependencyGraph) writeMermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	var highlightedNodes, highlightedEdges []string
+	for _, n := range g.nodes {
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", n.id, strings.ReplaceAll(n.label, `"`, "#quot;"))
+		if n.highlighted {
+			highlightedNodes = append(highlightedNodes, n.id)
+		}
+	}
+	for i, e := range g.edges {
+		if e.scope != "" {
+			fmt.Fprintf(&sb, "  %s -->|%s| %s\n", g.nodes[e.from].id, e.scope, g.nodes[e.to].id)
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s\n", g.nodes[e.from].id, g.nodes[e.to].id)
+		}
+		if e.highlighted {
+			highlightedEdges = append(highlightedEdges, strconv.Itoa(i))
+		}
+	}
+	if len(highlightedNodes) > 0 {
+		sb.WriteString("  classDef highlight stroke:#d00,stroke-width:2px\n")
+		fmt.Fprintf(&sb, "  class %s highlight\n", strings.Join(highlightedNodes, ","))
+	}
+	if len(highlightedEdges) > 0 {
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:#d00,stroke-width:2px\n", strings.Join(highlightedEdges, ","))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// graphML 及以下类型对应 GraphML 文档的结构
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+	Default  string `xml:"default,omitempty"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// writeGraphML 输出 GraphML 格式，节点带有 label，边带有 scope，高亮信息记录在 highlight 属性中
+func (g *dependencyGraph) writeGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "scope", For: "edge", AttrName: "scope", AttrType: "string"},
+			{ID: "highlight", For: "all", AttrName: "highlight", AttrType: "boolean", Default: "false"},
+		},
+		Graph: graphMLGraph{ID: "G", EdgeDefault: "directed"},
+	}
+	for _, n := range g.nodes {
+		node := graphMLNode{ID: n.id, Data: []graphMLData{{Key: "label", Value: n.label}}}
+		if n.highlighted {
+			node.Data = append(node.Data, graphMLData{Key: "highlight", Value: "true"})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for i, e := range g.edges {
+		edge := graphMLEdge{ID: "e" + strconv.Itoa(i), Source: g.nodes[e.from].id, Target: g.nodes[e.to].id}
+		if e.scope != "" {
+			edge.Data = append(edge.Data, graphMLData{Key: "scope", Value: e.scope})
+		}
+		if e.highlighted {
+			edge.Data = append(edge.Data, graphMLData{Key: "highlight", Value: "true"})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("写出 GraphML 失败: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,140 @@
+package pom_component_parsing
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// testDependencyTree 返回一个用于渲染测试的依赖树：
+// app -> spring-web -> spring-core -> commons-logging，app -> junit(test) -> hamcrest，app -> spring-core
+func testDependencyTree() Dependency {
+	dep := func(g, a, v, scope string, children ...Dependency) Dependency {
+		return Dependency{Coordinate: Coordinate{GroupId: g, ArtifactId: a, Version: v}, Scope: scope, Children: children}
+	}
+	logging := dep("commons-logging", "commons-logging", "1.2", "compile")
+	core := dep("org.springframework", "spring-core", "5.3.30", "compile", logging)
+	return dep("com.example", "app", "1.0.0", "",
+		dep("org.springframework", "spring-web", "5.3.30", "compile", core),
+		dep("junit", "junit", "4.13.2", "test", dep("org.hamcrest", "hamcrest-core", "1.3", "test")),
+		core,
+	)
+}
+
+func TestRenderGraph_DOT(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderGraph(&buf, testDependencyTree(), GraphFormatDOT, GraphOptions{Highlight: "commons-logging:commons-logging"})
+	if err != nil {
+		t.Fatalf("RenderGraph() error = %v", err)
+	}
+
+	want := `digraph "com.example:app:1.0.0" {
+  rankdir=LR;
+  node [shape=box];
+  n0 [label="com.example:app:1.0.0", color="red", penwidth=2];
+  n1 [label="org.springframework:spring-web:5.3.30", color="red", penwidth=2];
+  n2 [label="org.springframework:spring-core:5.3.30", color="red", penwidth=2];
+  n3 [label="commons-logging:commons-logging:1.2", color="red", penwidth=2];
+  n4 [label="junit:junit:4.13.2"];
+  n5 [label="org.hamcrest:hamcrest-core:1.3"];
+  n2 -> n3 [label="compile", color="red", penwidth=2];
+  n1 -> n2 [label="compile", color="red", penwidth=2];
+  n0 -> n1 [label="compile", color="red", penwidth=2];
+  n4 -> n5 [label="test"];
+  n0 -> n4 [label="test"];
+  n0 -> n2 [label="compile", color="red", penwidth=2];
+}
+`
+	if buf.String() != want {
+		t.Errorf("RenderGraph() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestRenderGraph_Options(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       GraphOptions
+		wantNodes  int
+		wantEdges  int
+		contains   []string
+		notContain []string
+	}{
+		{
+			name:       "按作用域过滤",
+			opts:       GraphOptions{Scopes: []string{"compile"}},
+			wantNodes:  4,
+			wantEdges:  4,
+			notContain: []string{"junit", "hamcrest"},
+		},
+		{
+			name:       "限制深度",
+			opts:       GraphOptions{MaxDepth: 1},
+			wantNodes:  4,
+			wantEdges:  3,
+			notContain: []string{"commons-logging", "hamcrest"},
+		},
+		{
+			name:      "按 groupId 合并",
+			opts:      GraphOptions{CollapseGroupId: true},
+			wantNodes: 5,
+			wantEdges: 4,
+			contains:  []string{`n1["org.springframework"]`, "n0 -->|compile| n1"},
+		},
+		{
+			name:      "高亮",
+			opts:      GraphOptions{Highlight: "org.hamcrest:hamcrest-core:1.3"},
+			wantNodes: 6,
+			wantEdges: 6,
+			contains:  []string{"class n0,n4,n5 highlight", "linkStyle 3,4 stroke:#d00,stroke-width:2px"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := buildDependencyGraph(testDependencyTree(), tt.opts)
+			if len(g.nodes) != tt.wantNodes || len(g.edges) != tt.wantEdges {
+				t.Errorf("节点数 = %d, 边数 = %d, want %d, %d", len(g.nodes), len(g.edges), tt.wantNodes, tt.wantEdges)
+			}
+
+			var buf bytes.Buffer
+			if err := RenderGraph(&buf, testDependencyTree(), GraphFormatMermaid, tt.opts); err != nil {
+				t.Fatalf("RenderGraph() error = %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("Mermaid 输出中缺少 %q\n%s", s, buf.String())
+				}
+			}
+			for _, s := range tt.notContain {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("Mermaid 输出中不应包含 %q\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRenderGraph_GraphML(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderGraph(&buf, testDependencyTree(), GraphFormatGraphML, GraphOptions{Highlight: "junit:junit"})
+	if err != nil {
+		t.Fatalf("RenderGraph() error = %v", err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("输出不是合法的 XML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != 6 || len(doc.Graph.Edges) != 6 {
+		t.Errorf("节点数 = %d, 边数 = %d", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if !strings.Contains(buf.String(), `<edge id="e4" source="n0" target="n4">`) ||
+		!strings.Contains(buf.String(), `<data key="highlight">true</data>`) {
+		t.Errorf("GraphML 输出不正确:\n%s", buf.String())
+	}
+
+	if err := RenderGraph(&buf, testDependencyTree(), "svg", GraphOptions{}); err == nil {
+		t.Errorf("不支持的格式应返回错误")
+	}
+}
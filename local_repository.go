@@ -0,0 +1,194 @@
+package pom_component_parsing
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liwenson/pom_component_parsing/model"
+	"go.uber.org/zap"
+)
+
+// LocalRepository 表示 Maven 本地仓库（默认 ~/.m2/repository）
+type LocalRepository struct {
+	Dir    string      // 本地仓库根目录
+	Logger *zap.Logger // 记录读取元数据时可忽略的错误，为 nil 时不输出日志
+}
+
+// NewLocalRepository 使用指定目录创建本地仓库
+func NewLocalRepository(dir string) *LocalRepository {
+	return &LocalRepository{Dir: dir}
+}
+
+// DefaultLocalRepository 返回 Maven 实际使用的本地仓库
+// 优先使用 settings.xml 中的 localRepository 配置，settingsPath 为空时读取 ~/.m2/settings.xml
+func DefaultLocalRepository(settingsPath string) *LocalRepository {
+	for _, settings := range loadEffectiveSettings(settingsPath) {
+		if settings.LocalRepository == "" {
+			continue
+		}
+		// localRepository 中允许使用 ${user.home} 等属性
+		dir, err := NewInterpolator(nil).Interpolate(settings.LocalRepository)
+		if err != nil {
+			// 无法插值的配置视为未配置，继续查找下一个 settings.xml
+			continue
+		}
+		return NewLocalRepository(dir)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return NewLocalRepository(filepath.Join(".m2", "repository"))
+	}
+	return NewLocalRepository(filepath.Join(home, ".m2", "repository"))
+}
+
+// ArtifactDir 返回坐标在本地仓库中的版本目录，如 repo/org/slf4j/slf4j-api/2.0.9
+func (l *LocalRepository) ArtifactDir(c Coordinate) string {
+	return filepath.Join(l.MetadataDir(c.GroupId, c.ArtifactId), c.Version)
+}
+
+// MetadataDir 返回 groupId:artifactId 在本地仓库中的目录，其中存放 maven-metadata-*.xml
+func (l *LocalRepository) MetadataDir(groupId, artifactId string) string {
+	return filepath.Join(l.Dir, filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), artifactId)
+}
+
+// PomPath 返回坐标对应 pom 文件在本地仓库中的路径
+func (l *LocalRepository) PomPath(c Coordinate) string {
+	return filepath.Join(l.ArtifactDir(c), c.ArtifactId+"-"+c.Version+".pom")
+}
+
+// artifactExtensions 是文件扩展名与类型不同的常见工件类型
+var artifactExtensions = map[string]string{
+	"test-jar":     "jar",
+	"maven-plugin": "jar",
+	"ejb":          "jar",
+	"ejb-client":   "jar",
+	"java-source":  "jar",
+	"javadoc":      "jar",
+	"bundle":       "jar",
+}
+
+// ArtifactPath 返回坐标对应工件文件在本地仓库中的路径，如 repo/io/netty/netty-transport-native-epoll/4.1.100.Final/netty-transport-native-epoll-4.1.100.Final-linux-x86_64.jar
+func (l *LocalRepository) ArtifactPath(c Coordinate) string {
+	c = c.Normalize()
+	ext := c.ArtifactType()
+	if e, ok := artifactExtensions[ext]; ok {
+		ext = e
+	}
+	classifier := c.Classifier
+	if classifier == "" && c.Type == "test-jar" {
+		classifier = "tests"
+	}
+
+	name := c.ArtifactId + "-" + c.Version
+	if classifier != "" {
+		name += "-" + classifier
+	}
+	return filepath.Join(l.ArtifactDir(c), name+"."+ext)
+}
+
+// ComponentPath 返回 Maven 组件在本地仓库中的工件文件路径，不是 Maven 组件或坐标不完整时返回空字符串。
+// LocalRepository 因此可以用作 sbom.ArtifactLocator
+func (l *LocalRepository) ComponentPath(component model.Component) string {
+	if component.Ecosystem != EcoRepo.Ecosystem {
+		return ""
+	}
+	c := componentCoordinate(component)
+	if !c.Complete() {
+		return ""
+	}
+	return l.ArtifactPath(c)
+}
+
+// FindPom 查找坐标对应的 pom 文件，文件不存在时返回 false
+func (l *LocalRepository) FindPom(c Coordinate) (string, bool) {
+	if !c.Complete() {
+		return "", false
+	}
+	path := l.PomPath(c)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// Contains 判断路径是否位于本地仓库中
+func (l *LocalRepository) Contains(path string) bool {
+	rel, err := filepath.Rel(l.Dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// repositoryMetadata 表示 maven-metadata.xml 中与版本相关的内容
+type repositoryMetadata struct {
+	Versioning struct {
+		Latest   string   `xml:"latest"`
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+// AvailableVersions 读取 maven-metadata-local.xml 与各远程仓库的 maven-metadata-*.xml，
+// 返回按 Maven 规则升序排列的去重版本列表
+func (l *LocalRepository) AvailableVersions(groupId, artifactId string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(l.MetadataDir(groupId, artifactId), "maven-metadata*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("本地仓库中没有 %s:%s 的 maven-metadata 文件", groupId, artifactId)
+	}
+
+	seen := map[string]struct{}{}
+	var versions []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			loggerOrNop(l.Logger).Warn("读取仓库元数据时出错", zap.String("path", file), zap.Error(err))
+			continue
+		}
+		var metadata repositoryMetadata
+		if err := xml.Unmarshal(data, &metadata); err != nil {
+			loggerOrNop(l.Logger).Warn("解析仓库元数据时出错", zap.String("path", file), zap.Error(err))
+			continue
+		}
+		for _, v := range metadata.Versioning.Versions {
+			v = strings.TrimSpace(v)
+			if _, ok := seen[v]; ok || v == "" {
+				continue
+			}
+			seen[v] = struct{}{}
+			versions = append(versions, v)
+		}
+	}
+
+	SortVersions(versions)
+	return versions, nil
+}
+
+// ResolveVersion 将版本要求解析为具体版本
+// 范围形式（如 [1.2,2.0)）使用本地仓库元数据中满足范围的最高版本，其他形式原样返回
+func (l *LocalRepository) ResolveVersion(groupId, artifactId, version string) (string, error) {
+	if !IsVersionRange(version) {
+		return version, nil
+	}
+
+	vr, err := ParseVersionRange(version)
+	if err != nil {
+		return "", err
+	}
+	available, err := l.AvailableVersions(groupId, artifactId)
+	if err != nil {
+		return "", err
+	}
+	resolved, ok := vr.MatchVersion(available)
+	if !ok {
+		return "", fmt.Errorf("本地仓库中没有 %s:%s 满足 %s 的版本", groupId, artifactId, version)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,122 @@
+package pom_component_parsing
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+func TestLocalRepository_PomPath(t *testing.T) {
+	repo := NewLocalRepository("/repo")
+	c := Coordinate{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "2.0.9"}
+
+	want := filepath.FromSlash("/repo/org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.pom")
+	if got := repo.PomPath(c); got != want {
+		t.Errorf("PomPath() = %v, want %v", got, want)
+	}
+	if !repo.Contains(want) {
+		t.Errorf("Contains(%v) = false, want true", want)
+	}
+	if repo.Contains(filepath.FromSlash("/repository/x.pom")) {
+		t.Errorf("Contains() 对仓库外的路径应返回 false")
+	}
+}
+
+func TestDefaultLocalRepository_FromSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", "")
+
+	// 没有 settings.xml 时使用 ~/.m2/repository
+	if got, want := DefaultLocalRepository("").Dir, filepath.Join(home, ".m2", "repository"); got != want {
+		t.Errorf("DefaultLocalRepository() = %v, want %v", got, want)
+	}
+
+	settings := writeTestFile(t, home, ".m2/settings.xml", `<settings>
+  <localRepository>${user.home}/custom-repo</localRepository>
+</settings>`)
+	if got, want := DefaultLocalRepository("").Dir, filepath.Join(home, "custom-repo"); got != want {
+		t.Errorf("DefaultLocalRepository() = %v, want %v", got, want)
+	}
+	if got, want := DefaultLocalRepository(settings).Dir, filepath.Join(home, "custom-repo"); got != want {
+		t.Errorf("DefaultLocalRepository(settings) = %v, want %v", got, want)
+	}
+}
+
+func TestLocalRepository_ResolveVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "org/example/lib/maven-metadata-local.xml", `<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>lib</artifactId>
+  <versioning>
+    <versions>
+      <version>1.0</version>
+      <version>1.5</version>
+    </versions>
+  </versioning>
+</metadata>`)
+	writeTestFile(t, dir, "org/example/lib/maven-metadata-central.xml", `<metadata>
+  <versioning>
+    <versions>
+      <version>1.5</version>
+      <version>1.10</version>
+      <version>2.0</version>
+    </versions>
+  </versioning>
+</metadata>`)
+	repo := NewLocalRepository(dir)
+
+	versions, err := repo.AvailableVersions("org.example", "lib")
+	if err != nil {
+		t.Fatalf("AvailableVersions() error = %v", err)
+	}
+	if want := []string{"1.0", "1.5", "1.10", "2.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("AvailableVersions() = %v, want %v", versions, want)
+	}
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "[1.2,2.0)", want: "1.10"},
+		{version: "1.3", want: "1.3"},
+		{version: "[3.0,)", wantErr: true},
+		{version: "[2.0,1.0]", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := repo.ResolveVersion("org.example", "lib", tt.version)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ResolveVersion(%q) = (%v, %v), want %v", tt.version, got, err, tt.want)
+		}
+	}
+
+	if _, err := repo.ResolveVersion("org.example", "missing", "[1.0,)"); err == nil {
+		t.Errorf("没有 maven-metadata 文件时应返回错误")
+	}
+}
+
+func TestLocalRepository_ArtifactPath(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewLocalRepository(dir)
+
+	epoll := Coordinate{GroupId: "io.netty", ArtifactId: "netty-transport-native-epoll", Version: "4.1.100.Final", Classifier: "linux-x86_64"}
+	if got, want := repo.ArtifactPath(epoll), filepath.Join(dir, "io/netty/netty-transport-native-epoll/4.1.100.Final/netty-transport-native-epoll-4.1.100.Final-linux-x86_64.jar"); got != want {
+		t.Errorf("ArtifactPath() = %v, want %v", got, want)
+	}
+	core := Coordinate{GroupId: "com.example", ArtifactId: "core", Version: "1.0.0", Type: "test-jar"}
+	if got, want := filepath.Base(repo.ArtifactPath(core)), "core-1.0.0-tests.jar"; got != want {
+		t.Errorf("ArtifactPath() = %v, want %v", got, want)
+	}
+
+	component := model.Component{CompName: epoll.Name(), CompVersion: epoll.Version, CompClassifier: epoll.Classifier, EcoRepo: EcoRepo}
+	if got, want := repo.ComponentPath(component), repo.ArtifactPath(epoll); got != want {
+		t.Errorf("ComponentPath() = %v, want %v", got, want)
+	}
+	if got := repo.ComponentPath(model.Component{CompName: "com.example:incomplete", EcoRepo: EcoRepo}); got != "" {
+		t.Errorf("坐标不完整时 ComponentPath() = %v, want 空字符串", got)
+	}
+}
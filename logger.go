@@ -0,0 +1,11 @@
+package pom_component_parsing
+
+import "go.uber.org/zap"
+
+// loggerOrNop 返回 logger，logger 为空时返回不输出任何日志的 zap.NewNop()
+func loggerOrNop(logger *zap.Logger) *zap.Logger {
+	if logger == nil {
+		return zap.NewNop()
+	}
+	return logger
+}
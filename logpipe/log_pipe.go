@@ -4,13 +4,18 @@ import (
 	"bufio"
 	"go.uber.org/zap"
 	"io"
+	"strings"
 	"sync/atomic"
 	"time"
 )
 
+// MaxLineLength 是传给 Option.OnLine 与日志的单行最大长度，超过的部分被丢弃
+const MaxLineLength = 1024 * 1024
+
 // Pipe 用于将数据写入管道并记录最后一行的时间戳
 type Pipe struct {
 	w                 *io.PipeWriter // 管道的写入端
+	done              chan struct{}  // 后台协程处理完所有数据后关闭
 	LastLineTimestamp atomic.Value   // 最后一行的时间戳，使用 atomic.Value 以支持任意类型存储
 }
 
@@ -21,15 +26,23 @@ func (p *Pipe) Write(data []byte) (int, error) {
 	return n, err
 }
 
-// Close 关闭管道的写入端
+// LastLine 返回最后一行的时间戳，尚未写入任何一行时返回零值
+func (p *Pipe) LastLine() time.Time {
+	return p.LastLineTimestamp.Load().(time.Time)
+}
+
+// Close 关闭管道的写入端，并等待已写入的数据处理完成
 func (p *Pipe) Close() error {
-	return p.w.Close()
+	err := p.w.Close()
+	<-p.done
+	return err
 }
 
 // Option 定义用于创建 Pipe 的选项
 type Option struct {
-	Logger *zap.Logger // 日志记录器，如果为 nil 则使用默认无操作记录器
-	Prefix string      // 日志前缀
+	Logger *zap.Logger  // 日志记录器，如果为 nil 则使用默认无操作记录器
+	Prefix string       // 日志前缀
+	OnLine func(string) // 每读取到一行时调用，为 nil 时忽略；在后台协程中按顺序调用
 }
 
 // NewWithOption 使用指定的选项创建一个新的 Pipe 实例
@@ -44,13 +57,13 @@ func NewWithOption(option Option) *Pipe {
 
 	// 创建管道的读写端
 	r, w := io.Pipe()
-	// 使用 bufio.Scanner 按行扫描管道中的数据
-	scanner := bufio.NewScanner(r)
-	scanner.Split(bufio.ScanLines)
+	// 使用 bufio.Reader 按行读取管道中的数据，超过缓冲区大小的行分多次读取，不会中断后续的行
+	reader := bufio.NewReader(r)
 
 	// 初始化 Pipe 结构体
 	pipe := &Pipe{
-		w: w,
+		w:    w,
+		done: make(chan struct{}),
 	}
 	// 初始化 LastLineTimestamp
 	var initialTime time.Time
@@ -58,24 +71,45 @@ func NewWithOption(option Option) *Pipe {
 
 	// 启动后台协程处理管道中的数据
 	go func() {
+		defer close(pipe.done)
 		defer r.Close() // 确保在协程结束时关闭读端
 
-		for scanner.Scan() {
-			// 获取当前时间
-			now := time.Now()
-			// 更新最后一行的时间戳
-			pipe.LastLineTimestamp.Store(now)
-			// 使用结构化日志记录日志信息
-			logger.Debug("Log line",
-				zap.String("prefix", option.Prefix),
-				zap.String("message", scanner.Text()),
-				zap.Time("timestamp", now),
-			)
-		}
-
-		// 检查扫描过程中是否发生错误
-		if err := scanner.Err(); err != nil {
-			logger.Error("Error scanning pipe", zap.Error(err))
+		var line []byte
+		for {
+			chunk, err := reader.ReadSlice('\n')
+			if len(chunk) > 0 {
+				// 超长行的每一段都视为新的输出，避免输出超长行时被误判为空闲
+				pipe.LastLineTimestamp.Store(time.Now())
+				// 超过 MaxLineLength 的部分丢弃
+				if n := MaxLineLength - len(line); n > 0 {
+					line = append(line, chunk[:min(n, len(chunk))]...)
+				}
+			}
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			// 以换行符结尾的行，或者没有换行符结尾的最后一行
+			if err == nil || len(line) > 0 {
+				text := strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r")
+				line = line[:0]
+				if option.OnLine != nil {
+					option.OnLine(text)
+				}
+				// 使用结构化日志记录日志信息
+				logger.Debug("Log line",
+					zap.String("prefix", option.Prefix),
+					zap.String("message", text),
+					zap.Time("timestamp", pipe.LastLine()),
+				)
+			}
+			if err != nil {
+				if err != io.EOF {
+					logger.Error("Error reading pipe", zap.Error(err))
+					// 继续读取并丢弃剩余数据，避免写入端阻塞
+					_, _ = io.Copy(io.Discard, r)
+				}
+				return
+			}
 		}
 	}()
 
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -168,3 +169,58 @@ func TestPipe_ConcurrentWrites(t *testing.T) {
 
 	p.Close()
 }
+
+func TestPipe_LongLine(t *testing.T) {
+	var lines []string
+	p := NewWithOption(Option{Prefix: "test-prefix", OnLine: func(line string) {
+		lines = append(lines, line)
+	}})
+	assert.True(t, p.LastLine().IsZero())
+
+	// 超过 bufio.Reader 缓冲区大小的行不应导致后续写入阻塞，也不应中断后续行的处理
+	long := strings.Repeat("a", 128*1024)
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Write([]byte(long + "\n"))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("写入超长行后阻塞")
+	}
+	afterLong := p.LastLine()
+	assert.False(t, afterLong.IsZero())
+
+	// 超长行之后的行仍然更新时间戳并调用 OnLine
+	time.Sleep(10 * time.Millisecond)
+	_, err := p.Write([]byte("test message\n"))
+	assert.NoError(t, err)
+	// 超过 MaxLineLength 的部分被丢弃
+	_, err = p.Write([]byte(strings.Repeat("b", MaxLineLength+10) + "\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, p.Close())
+
+	assert.True(t, p.LastLine().After(afterLong))
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, long, lines[0])
+		assert.Equal(t, "test message", lines[1])
+		assert.Equal(t, strings.Repeat("b", MaxLineLength), lines[2])
+	}
+}
+
+func TestOption_OnLine(t *testing.T) {
+	var lines []string
+	p := NewWithOption(Option{OnLine: func(line string) {
+		lines = append(lines, line)
+	}})
+
+	_, err := p.Write([]byte("line1\nline2\nline3"))
+	assert.NoError(t, err)
+
+	// Close 返回时所有行都已处理，包括没有换行符结尾的最后一行
+	assert.NoError(t, p.Close())
+	assert.Equal(t, []string{"line1", "line2", "line3"}, lines)
+}
@@ -1,10 +1,12 @@
 package pom_component_parsing
 
 import (
+	"context"
 	"fmt"
 	"github.com/liwenson/pom_component_parsing/model"
-	"log"
+	"go.uber.org/zap"
 	"path/filepath"
+	"strings"
 )
 
 // Dependency 表示一个Maven依赖项，包含坐标信息、子依赖和作用域。
@@ -25,25 +27,33 @@ func (d Dependency) String() string {
 }
 
 // ScanMavenProject 扫描指定目录下的Maven项目，返回模块列表或错误。
+// 使用默认选项的 ScanMavenProjectWithOptions：depgraph 插件、dependency:tree、纯 Go 的 POM 解析器依次回退。
+// 全部失败时返回 *ScanError，可以使用 errors.Is/As 判断 ErrMvnNotFound、ErrJavaNotFound、ErrMvnTimeout、*MvnBuildError 等具体原因。
+// 该函数使用 context.Background()，无法中途取消；需要取消扫描时使用 ScanMavenProjectWithOptions，
+// ctx 取消时会终止整个 Maven 进程组并返回 ctx 的错误。
 func ScanMavenProject(dir string) ([]model.Module, error) {
+	return ScanMavenProjectWithOptions(context.Background(), dir, ScanOptions{})
+}
+
+// ScanMavenProjectWithScanners 按给定的顺序依次尝试扫描策略，使用第一个得到结果的策略构建模块列表，
+// 每个模块的 ScanStrategy 记录实际产生结果的策略。ctx 取消时返回 ctx 的错误，
+// 所有策略都失败时返回 *ScanError，errors.Is(err, ErrInspection) 成立。
+func ScanMavenProjectWithScanners(ctx context.Context, dir string, scanners ...Scanner) ([]model.Module, error) {
+	return scanMavenProject(ctx, zap.NewNop(), dir, scanners, false)
+}
+
+// scanMavenProject 与 ScanMavenProjectWithScanners 相同，allowPartial 为 true 时
+// 部分模块扫描失败仍返回成功的模块，同时返回 *PartialResultError
+func scanMavenProject(ctx context.Context, logger *zap.Logger, dir string, scanners []Scanner, allowPartial bool) ([]model.Module, error) {
 	var modules []model.Module
-	var deps *DepsMap
-
-	// 检查Maven命令是否可用，若不可用则跳过扫描
-	mvnCmdInfo, err := CheckMvnCommand()
-	if err != nil {
-		log.Println("检查Maven命令时出错:", err)
-	} else {
-		// 使用Maven插件命令扫描依赖
-		deps, err = ScanDepsByPluginCommand(dir, mvnCmdInfo)
-		if err != nil {
-			log.Println("使用插件命令扫描依赖时出错:", err)
-		}
-	}
 
-	// 如果依赖映射为空，返回检查错误
-	if deps == nil {
-		return nil, ErrInspection
+	deps, strategy, err := scanDeps(ctx, logger, dir, scanners, allowPartial)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	// 如果所有策略都失败，返回记录了每个策略错误的 *ScanError
+	if err != nil && !isPartialResult(err) {
+		return nil, err
 	}
 
 	// 遍历所有依赖项，构建模块信息
@@ -53,12 +63,12 @@ func ScanMavenProject(dir string) ([]model.Module, error) {
 			ModuleName:     entry.coordinate.Name(),
 			ModuleVersion:  entry.coordinate.Version,
 			ModulePath:     filepath.Join(dir, entry.relativePath),
+			ScanStrategy:   string(strategy),
 			Dependencies:   convDeps(entry.children),
-			// ScanStrategy:   strategy, // 可根据需要启用扫描策略
 		})
 	}
 
-	return modules, nil
+	return modules, err
 }
 
 // convDeps 将内部的Dependency切片转换为模型层的DependencyItem切片。
@@ -69,7 +79,7 @@ func convDeps(deps []Dependency) []model.DependencyItem {
 		if d == nil {
 			continue
 		}
-		//d.IsDirectDependency = true // 标记为直接依赖
+		d.IsDirectDependency = true // 标记为直接依赖
 		rs = append(rs, *d)
 	}
 	return rs
@@ -85,9 +95,11 @@ func _convDep(dep Dependency) *model.DependencyItem {
 	// 创建DependencyItem并填充基本信息
 	d := &model.DependencyItem{
 		Component: model.Component{
-			CompName:    dep.Name(),
-			CompVersion: dep.Version,
-			EcoRepo:     EcoRepo,
+			CompName:       dep.Name(),
+			CompVersion:    dep.Version,
+			CompType:       dep.Type,
+			CompClassifier: dep.Classifier,
+			EcoRepo:        EcoRepo,
 		},
 		IsOnline:   model.IsOnlineTrue(),
 		MavenScope: dep.Scope,
@@ -109,11 +121,45 @@ func _convDep(dep Dependency) *model.DependencyItem {
 	return d
 }
 
+// ModuleDependencyTree 将模型层的模块转换为以模块自身为根的依赖树，是 convDeps 的逆操作
+func ModuleDependencyTree(m model.Module) Dependency {
+	groupId, artifactId, _ := strings.Cut(m.ModuleName, ":")
+	return Dependency{
+		Coordinate: Coordinate{GroupId: groupId, ArtifactId: artifactId, Version: m.ModuleVersion},
+		Children:   dependenciesOfItems(m.Dependencies),
+	}
+}
+
+// dependenciesOfItems 递归地将模型层的 DependencyItem 转换为 Dependency
+func dependenciesOfItems(items []model.DependencyItem) []Dependency {
+	var rs []Dependency
+	for _, it := range items {
+		rs = append(rs, Dependency{
+			Coordinate: componentCoordinate(it.Component),
+			Scope:      it.MavenScope,
+			Children:   dependenciesOfItems(it.Dependencies),
+		})
+	}
+	return rs
+}
+
+// componentCoordinate 根据模型层的组件构造坐标，组件名称的格式为 groupId:artifactId
+func componentCoordinate(c model.Component) Coordinate {
+	groupId, artifactId, _ := strings.Cut(c.CompName, ":")
+	return Coordinate{
+		GroupId:    groupId,
+		ArtifactId: artifactId,
+		Version:    c.CompVersion,
+		Type:       c.CompType,
+		Classifier: c.CompClassifier,
+	}
+}
+
 // EcoRepo 定义了生态系统和仓库信息，用于DependencyItem。
 var EcoRepo = model.EcoRepo{
 	Ecosystem:  "maven",
 	Repository: "",
 }
 
-// ErrInspection 表示依赖检查失败的错误。
+// ErrInspection 表示依赖检查失败的错误，所有扫描策略都失败时返回的 *ScanError 满足 errors.Is(err, ErrInspection)。
 var ErrInspection = fmt.Errorf("依赖检查失败")
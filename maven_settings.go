@@ -0,0 +1,77 @@
+package pom_component_parsing
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+// MavenSettings 表示 Maven settings.xml 中与依赖解析相关的配置
+type MavenSettings struct {
+	Path            string          `xml:"-"`                            // settings.xml 文件路径
+	LocalRepository string          `xml:"localRepository"`              // 本地仓库路径，为空表示使用默认路径
+	Offline         bool            `xml:"offline"`                      // 是否为离线模式
+	Profiles        []gopom.Profile `xml:"profiles>profile"`             // settings.xml 中声明的 profile
+	ActiveProfiles  []string        `xml:"activeProfiles>activeProfile"` // 始终激活的 profile ID
+}
+
+// LoadMavenSettings 读取并解析指定路径的 settings.xml
+func LoadMavenSettings(path string) (*MavenSettings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 settings.xml 失败: %w", err)
+	}
+
+	var settings MavenSettings
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("解析 settings.xml 失败: %w", err)
+	}
+	settings.Path = path
+	settings.LocalRepository = strings.TrimSpace(settings.LocalRepository)
+	for i, id := range settings.ActiveProfiles {
+		settings.ActiveProfiles[i] = strings.TrimSpace(id)
+	}
+	return &settings, nil
+}
+
+// DefaultUserSettingsPath 返回用户级 settings.xml 的默认路径 ~/.m2/settings.xml
+func DefaultUserSettingsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "settings.xml")
+}
+
+// globalSettingsPath 返回 Maven 安装目录下 conf/settings.xml 的路径
+func globalSettingsPath() string {
+	for _, env := range []string{"MAVEN_HOME", "M2_HOME"} {
+		if home := os.Getenv(env); home != "" {
+			return filepath.Join(home, "conf", "settings.xml")
+		}
+	}
+	return ""
+}
+
+// loadEffectiveSettings 依次加载指定的（或默认的）用户 settings.xml 与全局 settings.xml
+// 返回的列表中用户配置在前，读取失败的文件会被跳过
+func loadEffectiveSettings(userSettingsPath string) []*MavenSettings {
+	if userSettingsPath == "" {
+		userSettingsPath = DefaultUserSettingsPath()
+	}
+
+	var rs []*MavenSettings
+	for _, path := range []string{userSettingsPath, globalSettingsPath()} {
+		if path == "" {
+			continue
+		}
+		if settings, err := LoadMavenSettings(path); err == nil {
+			rs = append(rs, settings)
+		}
+	}
+	return rs
+}
@@ -187,6 +187,20 @@ func Test_convDep(t *testing.T) {
 	}
 }
 
+func TestModuleDependencyTree(t *testing.T) {
+	tree := testDependencyTree()
+	m := model.Module{
+		ModuleName:    tree.Coordinate.Name(),
+		ModuleVersion: tree.Coordinate.Version,
+		Dependencies:  convDeps(tree.Children),
+	}
+
+	got := ModuleDependencyTree(m)
+	if !reflect.DeepEqual(got, tree) {
+		t.Errorf("ModuleDependencyTree() = %v, want %v", got, tree)
+	}
+}
+
 func TestScanMavenProject(t *testing.T) {
 	dir := "workspace/newton_buyer"
 	modules, e := ScanMavenProject(dir)
@@ -2,9 +2,11 @@ package model
 
 // Component 结构体表示一个组件，包括名称、版本和生态仓库信息
 type Component struct {
-	CompName    string `json:"comp_name"`    // 组件名称
-	CompVersion string `json:"comp_version"` // 组件版本
-	//IsDirectDependency bool   `json:"is_direct_dependency"` // 是否为直接依赖
+	CompName           string `json:"comp_name"`                 // 组件名称
+	CompVersion        string `json:"comp_version"`              // 组件版本
+	CompType           string `json:"comp_type,omitempty"`       // 组件类型，如 Maven 的 jar、test-jar
+	CompClassifier     string `json:"comp_classifier,omitempty"` // 组件分类器，如 Maven 的 linux-x86_64
+	IsDirectDependency bool   `json:"is_direct_dependency"`      // 是否为直接依赖
 	//ModuleName         string `json:"module_name"`          // 模块名称
 	EcoRepo // 嵌入的生态仓库信息
 }
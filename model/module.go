@@ -1,16 +1,13 @@
 package model
 
-import (
-	"github.com/liwenson/pom_component_parsing/utils"
-)
-
 // Module 结构体用于表示一个模块的信息，包括名称、版本、路径、包管理器以及依赖项
 type Module struct {
-	ModuleName     string           `json:"module_name"`            // 模块的名称
-	ModuleVersion  string           `json:"module_version"`         // 模块的版本
-	ModulePath     string           `json:"module_path"`            // 模块的路径
-	PackageManager string           `json:"package_manager"`        // 使用的包管理器，例如 npm, maven 等
-	Dependencies   []DependencyItem `json:"dependencies,omitempty"` // 模块的依赖项列表，如果为空则在 JSON 中省略
+	ModuleName     string           `json:"module_name"`             // 模块的名称
+	ModuleVersion  string           `json:"module_version"`          // 模块的版本
+	ModulePath     string           `json:"module_path"`             // 模块的路径
+	PackageManager string           `json:"package_manager"`         // 使用的包管理器，例如 npm, maven 等
+	ScanStrategy   string           `json:"scan_strategy,omitempty"` // 产生该模块扫描结果的策略，例如 depgraph、pom-resolver 等
+	Dependencies   []DependencyItem `json:"dependencies,omitempty"`  // 模块的依赖项列表，如果为空则在 JSON 中省略
 }
 
 // String 返回模块的字符串表示，格式为 "[包管理器]模块名称@模块版本"
@@ -27,20 +24,27 @@ func (m Module) IsZero() bool {
 	return len(m.Dependencies) == 0 && m.ModuleName == "" && m.ModuleVersion == ""
 }
 
-// ComponentList 返回模块中所有的组件列表，包含所有直接和间接的依赖项
+// ComponentList 返回模块中所有的组件列表，包含所有直接和间接的依赖项。
+// 同一组件既是直接依赖又是间接依赖时只返回一次，IsDirectDependency 为 true
 func (m Module) ComponentList() []Component {
-	var r = make(map[Component]struct{})
+	var r = make(map[Component]bool)
 	collectComponents(m.Dependencies, r)
-	return utils.KeysOfMap(r)
+	rs := make([]Component, 0, len(r))
+	for c, direct := range r {
+		c.IsDirectDependency = direct
+		rs = append(rs, c)
+	}
+	return rs
 }
 
 // collectComponents 是一个辅助函数，用于递归遍历依赖项并收集所有的组件
-// 将模块名称添加到每个组件的 ModuleName 字段中
-func collectComponents(deps []DependencyItem, cm map[Component]struct{}) {
+// 以去掉 IsDirectDependency 后的组件为键去重，值记录该组件是否出现过直接依赖
+func collectComponents(deps []DependencyItem, cm map[Component]bool) {
 	for _, dep := range deps {
-		// 设置组件的 ModuleName 为当前模块的名称
 		// 将组件添加到 map 中以避免重复
-		cm[dep.Component] = struct{}{}
+		c := dep.Component
+		c.IsDirectDependency = false
+		cm[c] = cm[c] || dep.IsDirectDependency
 		// 递归处理该组件的依赖项
 		collectComponents(dep.Dependencies, cm)
 	}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestModule_ComponentList(t *testing.T) {
+	maven := EcoRepo{Ecosystem: "maven"}
+	guava := Component{CompName: "com.google.guava:guava", CompVersion: "32.1.2-jre", EcoRepo: maven}
+	failureaccess := Component{CompName: "com.google.guava:failureaccess", CompVersion: "1.0.1", EcoRepo: maven}
+	jsr305 := Component{CompName: "com.google.code.findbugs:jsr305", CompVersion: "3.0.2", EcoRepo: maven}
+
+	direct := func(c Component) Component {
+		c.IsDirectDependency = true
+		return c
+	}
+	m := Module{Dependencies: []DependencyItem{
+		{
+			Component:    direct(guava),
+			Dependencies: []DependencyItem{{Component: failureaccess}, {Component: jsr305}},
+		},
+		// failureaccess 同时是直接依赖与 guava 的传递依赖
+		{Component: direct(failureaccess)},
+		{
+			Component:    direct(Component{CompName: "com.example:lib", CompVersion: "1.0", EcoRepo: maven}),
+			Dependencies: []DependencyItem{{Component: guava}},
+		},
+	}}
+
+	got := m.ComponentList()
+	sort.Slice(got, func(i, j int) bool { return got[i].CompName < got[j].CompName })
+	want := []Component{
+		direct(Component{CompName: "com.example:lib", CompVersion: "1.0", EcoRepo: maven}),
+		jsr305,
+		direct(failureaccess),
+		direct(guava),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ComponentList() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ComponentList()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PackageURL 表示一个 Package URL（purl），格式为 pkg:type/namespace/name@version?qualifiers#subpath
+// 规范见 https://github.com/package-url/purl-spec
+type PackageURL struct {
+	Type       string            // 包类型，如 maven、npm、pypi
+	Namespace  string            // 命名空间，如 Maven 的 groupId、npm 的 @scope，可以为空
+	Name       string            // 包名称
+	Version    string            // 版本，可以为空
+	Qualifiers map[string]string // 限定符，如 Maven 的 type、classifier、repository_url
+	Subpath    string            // 包内的子路径，可以为空
+}
+
+// ErrInvalidPurl 表示 purl 的格式不正确
+var ErrInvalidPurl = errors.New("无效的 purl")
+
+// PurlTypeMaven 等为常用生态系统对应的 purl 类型
+const (
+	PurlTypeMaven    = "maven"
+	PurlTypeNpm      = "npm"
+	PurlTypePypi     = "pypi"
+	PurlTypeGolang   = "golang"
+	PurlTypeNuget    = "nuget"
+	PurlTypeGem      = "gem"
+	PurlTypeCargo    = "cargo"
+	PurlTypeComposer = "composer"
+	PurlTypeGeneric  = "generic"
+)
+
+// ecosystemPurlTypes 是 EcoRepo.Ecosystem 中常见的别名与 purl 类型的对应关系
+var ecosystemPurlTypes = map[string]string{
+	"pip":       PurlTypePypi,
+	"python":    PurlTypePypi,
+	"go":        PurlTypeGolang,
+	"gomod":     PurlTypeGolang,
+	"rubygems":  PurlTypeGem,
+	"ruby":      PurlTypeGem,
+	"crates.io": PurlTypeCargo,
+	"rust":      PurlTypeCargo,
+	"php":       PurlTypeComposer,
+	"dotnet":    PurlTypeNuget,
+	"yarn":      PurlTypeNpm,
+	"pnpm":      PurlTypeNpm,
+}
+
+// PurlType 返回生态系统对应的 purl 类型，无法识别的生态系统返回 generic
+func PurlType(ecosystem string) string {
+	ecosystem = strings.ToLower(strings.TrimSpace(ecosystem))
+	if t, ok := ecosystemPurlTypes[ecosystem]; ok {
+		return t
+	}
+	if !isValidPurlType(ecosystem) {
+		return PurlTypeGeneric
+	}
+	return ecosystem
+}
+
+// isValidPurlType 判断字符串是否为合法的 purl 类型：以字母开头，只包含字母、数字、'.'、'+'、'-'
+func isValidPurlType(t string) bool {
+	if t == "" || !(t[0] >= 'a' && t[0] <= 'z' || t[0] >= 'A' && t[0] <= 'Z') {
+		return false
+	}
+	for _, c := range t {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '+' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回 purl 的规范字符串形式，限定符按键排序，空值的限定符被忽略
+func (p PackageURL) String() string {
+	var sb strings.Builder
+	sb.WriteString("pkg:")
+	sb.WriteString(strings.ToLower(p.Type))
+	sb.WriteString("/")
+	if p.Namespace != "" {
+		for _, segment := range strings.Split(p.Namespace, "/") {
+			if segment == "" {
+				continue
+			}
+			sb.WriteString(escapePurl(segment))
+			sb.WriteString("/")
+		}
+	}
+	sb.WriteString(escapePurl(p.Name))
+	if p.Version != "" {
+		sb.WriteString("@")
+		sb.WriteString(escapePurl(p.Version))
+	}
+
+	var keys []string
+	for k, v := range p.Qualifiers {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+	for i, k := range keys {
+		if i == 0 {
+			sb.WriteString("?")
+		} else {
+			sb.WriteString("&")
+		}
+		sb.WriteString(strings.ToLower(k))
+		sb.WriteString("=")
+		sb.WriteString(escapePurl(p.Qualifiers[k]))
+	}
+
+	if subpath := strings.Trim(p.Subpath, "/"); subpath != "" {
+		sb.WriteString("#")
+		var segments []string
+		for _, segment := range strings.Split(subpath, "/") {
+			if segment != "" && segment != "." && segment != ".." {
+				segments = append(segments, escapePurl(segment))
+			}
+		}
+		sb.WriteString(strings.Join(segments, "/"))
+	}
+	return sb.String()
+}
+
+// ParsePackageURL 解析 purl 字符串
+func ParsePackageURL(s string) (PackageURL, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), "pkg:")
+	if !ok {
+		return PackageURL{}, fmt.Errorf("%w: %q 缺少 pkg: 前缀", ErrInvalidPurl, s)
+	}
+	// 规范允许 pkg: 之后出现多余的 /，如 pkg://maven/...
+	rest = strings.TrimLeft(rest, "/")
+
+	var p PackageURL
+	var err error
+
+	rest, subpath, _ := strings.Cut(rest, "#")
+	if p.Subpath, err = unescapePurl(strings.Trim(subpath, "/")); err != nil {
+		return PackageURL{}, fmt.Errorf("%w: %q 的 subpath 无法解码", ErrInvalidPurl, s)
+	}
+
+	rest, query, _ := strings.Cut(rest, "?")
+	if query != "" {
+		p.Qualifiers = map[string]string{}
+		for _, pair := range strings.Split(query, "&") {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || k == "" {
+				return PackageURL{}, fmt.Errorf("%w: %q 的限定符 %q 格式不正确", ErrInvalidPurl, s, pair)
+			}
+			if v, err = unescapePurl(v); err != nil {
+				return PackageURL{}, fmt.Errorf("%w: %q 的限定符 %q 无法解码", ErrInvalidPurl, s, pair)
+			}
+			if v != "" {
+				p.Qualifiers[strings.ToLower(k)] = v
+			}
+		}
+	}
+
+	t, rest, ok := strings.Cut(rest, "/")
+	if !ok || !isValidPurlType(t) {
+		return PackageURL{}, fmt.Errorf("%w: %q 的类型不正确", ErrInvalidPurl, s)
+	}
+	p.Type = strings.ToLower(t)
+
+	rest = strings.TrimRight(rest, "/")
+	// 版本以最后一个 '@' 分隔，且必须位于名称之后，以兼容未编码的 npm 作用域，如 pkg:npm/@angular/core
+	if idx := strings.LastIndex(rest, "@"); idx >= 0 && idx > strings.LastIndex(rest, "/") {
+		if p.Version, err = unescapePurl(rest[idx+1:]); err != nil {
+			return PackageURL{}, fmt.Errorf("%w: %q 的版本无法解码", ErrInvalidPurl, s)
+		}
+		rest = rest[:idx]
+	}
+
+	segments := strings.Split(rest, "/")
+	for i, segment := range segments {
+		if segments[i], err = unescapePurl(segment); err != nil {
+			return PackageURL{}, fmt.Errorf("%w: %q 的名称无法解码", ErrInvalidPurl, s)
+		}
+	}
+	p.Name = segments[len(segments)-1]
+	if p.Name == "" {
+		return PackageURL{}, fmt.Errorf("%w: %q 缺少名称", ErrInvalidPurl, s)
+	}
+	var namespace []string
+	for _, segment := range segments[:len(segments)-1] {
+		if segment != "" {
+			namespace = append(namespace, segment)
+		}
+	}
+	p.Namespace = strings.Join(namespace, "/")
+	return p, nil
+}
+
+// escapePurl 对 purl 的各个组成部分进行百分号编码，保留非保留字符与 ':'
+func escapePurl(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' || c == ':' {
+			sb.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", c)
+	}
+	return sb.String()
+}
+
+// unescapePurl 解码百分号编码，与 escapePurl 相反
+func unescapePurl(s string) (string, error) {
+	return url.PathUnescape(s)
+}
+
+// PackageURL 返回组件对应的 purl
+// Maven 组件名称 groupId:artifactId 拆分为命名空间与名称，type（非 jar 时）、classifier 与 repository_url 作为限定符；
+// 其他生态系统按最后一个 '/' 拆分命名空间，如 npm 的 @scope/name、Go 的模块路径
+func (c Component) PackageURL() PackageURL {
+	p := PackageURL{
+		Type:    PurlType(c.Ecosystem),
+		Name:    c.CompName,
+		Version: c.CompVersion,
+	}
+
+	switch p.Type {
+	case PurlTypeMaven:
+		if groupId, artifactId, ok := strings.Cut(c.CompName, ":"); ok {
+			p.Namespace, p.Name = groupId, artifactId
+		}
+	case PurlTypePypi:
+		// PyPI 名称不区分大小写，且 '_' 与 '-' 等价
+		p.Name = strings.ReplaceAll(strings.ToLower(c.CompName), "_", "-")
+	default:
+		if idx := strings.LastIndex(c.CompName, "/"); idx >= 0 {
+			p.Namespace, p.Name = c.CompName[:idx], c.CompName[idx+1:]
+		}
+	}
+
+	qualifiers := map[string]string{}
+	if c.CompType != "" && !(p.Type == PurlTypeMaven && c.CompType == "jar") {
+		qualifiers["type"] = c.CompType
+	}
+	if c.CompClassifier != "" {
+		qualifiers["classifier"] = c.CompClassifier
+	}
+	if c.Repository != "" {
+		qualifiers["repository_url"] = c.Repository
+	}
+	if len(qualifiers) > 0 {
+		p.Qualifiers = qualifiers
+	}
+	return p
+}
+
+// Purl 返回组件对应的 purl 字符串，如 pkg:maven/org.slf4j/slf4j-api@2.0.9
+func (c Component) Purl() string {
+	return c.PackageURL().String()
+}
+
+// ParseComponentPurl 将 purl 解析为组件，是 Component.Purl 的逆操作
+// Ecosystem 取 purl 的类型，Maven 组件名称还原为 groupId:artifactId
+func ParseComponentPurl(s string) (Component, error) {
+	p, err := ParsePackageURL(s)
+	if err != nil {
+		return Component{}, err
+	}
+
+	c := Component{
+		CompName:       p.Name,
+		CompVersion:    p.Version,
+		CompType:       p.Qualifiers["type"],
+		CompClassifier: p.Qualifiers["classifier"],
+		EcoRepo: EcoRepo{
+			Ecosystem:  p.Type,
+			Repository: p.Qualifiers["repository_url"],
+		},
+	}
+	if p.Namespace != "" {
+		if p.Type == PurlTypeMaven {
+			c.CompName = p.Namespace + ":" + p.Name
+		} else {
+			c.CompName = p.Namespace + "/" + p.Name
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestComponent_Purl(t *testing.T) {
+	tests := []struct {
+		name      string
+		component Component
+		want      string
+	}{
+		{
+			name:      "Maven",
+			component: Component{CompName: "org.slf4j:slf4j-api", CompVersion: "2.0.9", EcoRepo: EcoRepo{Ecosystem: "maven"}},
+			want:      "pkg:maven/org.slf4j/slf4j-api@2.0.9",
+		},
+		{
+			name: "Maven 类型、分类器与仓库",
+			component: Component{
+				CompName:       "io.netty:netty-transport-native-epoll",
+				CompVersion:    "4.1.100.Final",
+				CompType:       "jar",
+				CompClassifier: "linux-x86_64",
+				EcoRepo:        EcoRepo{Ecosystem: "maven", Repository: "https://repo.example.com/maven2"},
+			},
+			want: "pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64&repository_url=https:%2F%2Frepo.example.com%2Fmaven2",
+		},
+		{
+			name:      "Maven 非默认类型",
+			component: Component{CompName: "com.example:core", CompVersion: "1.0.0", CompType: "test-jar", EcoRepo: EcoRepo{Ecosystem: "maven"}},
+			want:      "pkg:maven/com.example/core@1.0.0?type=test-jar",
+		},
+		{
+			name:      "npm 作用域",
+			component: Component{CompName: "@angular/core", CompVersion: "16.2.0", EcoRepo: EcoRepo{Ecosystem: "npm"}},
+			want:      "pkg:npm/%40angular/core@16.2.0",
+		},
+		{
+			name:      "PyPI 名称规范化",
+			component: Component{CompName: "Django_Rest", CompVersion: "1.0", EcoRepo: EcoRepo{Ecosystem: "pip"}},
+			want:      "pkg:pypi/django-rest@1.0",
+		},
+		{
+			name:      "Go 模块",
+			component: Component{CompName: "github.com/gin-gonic/gin", CompVersion: "v1.9.1", EcoRepo: EcoRepo{Ecosystem: "go"}},
+			want:      "pkg:golang/github.com/gin-gonic/gin@v1.9.1",
+		},
+		{
+			name:      "未知生态系统",
+			component: Component{CompName: "openssl", CompVersion: "3.0.0", EcoRepo: EcoRepo{Ecosystem: "c/c++"}},
+			want:      "pkg:generic/openssl@3.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.component.Purl(); got != tt.want {
+				t.Errorf("Purl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseComponentPurl(t *testing.T) {
+	tests := []struct {
+		name    string
+		purl    string
+		want    Component
+		wantErr bool
+	}{
+		{
+			name: "Maven",
+			purl: "pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64&repository_url=https:%2F%2Frepo.example.com%2Fmaven2",
+			want: Component{
+				CompName:       "io.netty:netty-transport-native-epoll",
+				CompVersion:    "4.1.100.Final",
+				CompClassifier: "linux-x86_64",
+				EcoRepo:        EcoRepo{Ecosystem: "maven", Repository: "https://repo.example.com/maven2"},
+			},
+		},
+		{
+			name: "npm 未编码的作用域",
+			purl: "pkg:npm/@angular/core@16.2.0",
+			want: Component{CompName: "@angular/core", CompVersion: "16.2.0", EcoRepo: EcoRepo{Ecosystem: "npm"}},
+		},
+		{
+			name: "没有版本",
+			purl: "pkg:golang/github.com/gin-gonic/gin",
+			want: Component{CompName: "github.com/gin-gonic/gin", EcoRepo: EcoRepo{Ecosystem: "golang"}},
+		},
+		{
+			name: "类型大写与多余的斜杠",
+			purl: "pkg://PyPI/django@4.2#src/django",
+			want: Component{CompName: "django", CompVersion: "4.2", EcoRepo: EcoRepo{Ecosystem: "pypi"}},
+		},
+		{name: "缺少前缀", purl: "maven/org.slf4j/slf4j-api@2.0.9", wantErr: true},
+		{name: "缺少名称", purl: "pkg:maven/@1.0", wantErr: true},
+		{name: "非法类型", purl: "pkg:1maven/a@1", wantErr: true},
+		{name: "限定符格式不正确", purl: "pkg:maven/g/a@1?type", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseComponentPurl(tt.purl)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPurl) {
+					t.Errorf("ParseComponentPurl(%q) error = %v, want ErrInvalidPurl", tt.purl, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseComponentPurl(%q) error = %v", tt.purl, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseComponentPurl(%q) = %+v, want %+v", tt.purl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePackageURL_Subpath(t *testing.T) {
+	p, err := ParsePackageURL("pkg:golang/google.golang.org/genproto@abcdedf#googleapis/api/annotations")
+	if err != nil {
+		t.Fatalf("ParsePackageURL() error = %v", err)
+	}
+	want := PackageURL{Type: "golang", Namespace: "google.golang.org", Name: "genproto", Version: "abcdedf", Subpath: "googleapis/api/annotations"}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("ParsePackageURL() = %+v, want %+v", p, want)
+	}
+	if got := p.String(); got != "pkg:golang/google.golang.org/genproto@abcdedf#googleapis/api/annotations" {
+		t.Errorf("String() = %v", got)
+	}
+}
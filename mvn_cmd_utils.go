@@ -3,6 +3,8 @@
 package pom_component_parsing
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -13,6 +15,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/liwenson/pom_component_parsing/utils"
+	"go.uber.org/zap"
 )
 
 // MvnCommandInfo 存储 Maven 命令的相关配置信息
@@ -22,6 +27,7 @@ type MvnCommandInfo struct {
 	MvnVersion       string `json:"mvn_version"`        // Maven 的版本号（如 3.6.3）
 	UserSettingsPath string `json:"user_settings_path"` // Maven 用户配置文件 settings.xml 的路径
 	JavaHome         string `json:"java_home"`          // Java 安装目录的路径
+	JavaVersion      string `json:"java_version"`       // Maven 运行使用的 Java 版本（如 17.0.2），用于评估 profile 的 jdk 激活条件
 }
 
 // String 方法实现了 fmt.Stringer 接口，用于格式化输出 MvnCommandInfo 的信息
@@ -83,11 +89,25 @@ var (
 	ErrMvnNotFound = errors.New("Maven command not found")
 	// ErrCheckMvnVersion 表示检查 Maven 版本时发生错误
 	ErrCheckMvnVersion = errors.New("failed to check Maven version")
+	// ErrJavaNotFound 表示 Maven 找不到可用的 Java 运行环境，与 ErrCheckMvnVersion 一起返回
+	ErrJavaNotFound = errors.New("Java runtime not found")
 )
 
 // CheckMvnCommand 检查并返回系统中的 Maven 命令信息
-// 该函数会缓存检查结果，避免重复执行耗时的检查操作
+// 该函数会缓存检查结果，避免重复执行耗时的检查操作，需要中途取消时使用 CheckMvnCommandContext
 func CheckMvnCommand() (info *MvnCommandInfo, err error) {
+	return CheckMvnCommandContext(context.Background())
+}
+
+// CheckMvnCommandContext 与 CheckMvnCommand 相同，ctx 取消时终止正在执行的 mvn --version，
+// 因取消而失败的检查结果不会被缓存
+func CheckMvnCommandContext(ctx context.Context) (info *MvnCommandInfo, err error) {
+	return CheckMvnCommandWithLogger(ctx, nil)
+}
+
+// CheckMvnCommandWithLogger 与 CheckMvnCommandContext 相同，检查过程的日志写入 logger，logger 为空时不输出日志
+func CheckMvnCommandWithLogger(ctx context.Context, logger *zap.Logger) (info *MvnCommandInfo, err error) {
+	logger = loggerOrNop(logger)
 	// 尝试从缓存中读取结果
 	mu.RLock()
 	if cachedMvnCommandResult != nil {
@@ -117,18 +137,31 @@ func CheckMvnCommand() (info *MvnCommandInfo, err error) {
 	info.Path = getMvnCommandOs()
 	if info.Path == "" {
 		err = ErrMvnNotFound
+		logger.Warn("未找到 Maven 命令")
 		cachedMvnCommandResult = &_MvnCommandResult{rs: nil, e: err}
 		return
 	}
 
 	// 检查 Maven 版本
-	ver, e := checkMvnVersion(info.Path, info.JavaHome)
+	start := time.Now()
+	ver, javaVer, e := checkMvnVersion(ctx, logger, info.Path, info.JavaHome)
 	if e != nil {
 		err = e
-		cachedMvnCommandResult = &_MvnCommandResult{rs: info, e: err}
+		logger.Warn("检查 Maven 版本失败", zap.String("path", info.Path), zap.String("java_home", info.JavaHome), zap.Error(err))
+		if ctx.Err() == nil {
+			cachedMvnCommandResult = &_MvnCommandResult{rs: info, e: err}
+		}
 		return
 	}
 	info.MvnVersion = ver
+	info.JavaVersion = javaVer
+	logger.Info("检查 Maven 命令完成",
+		zap.String("path", info.Path),
+		zap.String("mvn_version", ver),
+		zap.String("java_home", info.JavaHome),
+		zap.String("java_version", javaVer),
+		zap.Duration("duration", time.Since(start)),
+	)
 
 	// 缓存检查结果
 	cachedMvnCommandResult = &_MvnCommandResult{
@@ -138,64 +171,88 @@ func CheckMvnCommand() (info *MvnCommandInfo, err error) {
 	return
 }
 
+// javaNotFoundPattern 匹配 mvn 脚本找不到 Java 时输出的错误信息，如
+// "The JAVA_HOME environment variable is not defined correctly" 与 "java: command not found"
+var javaNotFoundPattern = regexp.MustCompile(`JAVA_HOME (?:environment variable )?is not defined|JAVA_HOME is set to an invalid directory|java: (?:command )?not found`)
+
+// mvnVersionTimeout 是执行 mvn --version 的超时时间
+const mvnVersionTimeout = 8 * time.Second
+
 // executeMvnVersion 执行 Maven 命令获取版本信息
-// 支持超时控制，避免命令执行时间过长
-func executeMvnVersion(mvnPath string, javaHome string) (string, error) {
+// 支持超时控制与 ctx 取消，避免命令执行时间过长
+func executeMvnVersion(ctx context.Context, logger *zap.Logger, mvnPath string, javaHome string) (string, error) {
 	cmd := exec.Command(mvnPath, "--version", "--batch-mode")
+	utils.SetPGid(cmd)
 
 	// 设置环境变量
 	cmd.Env = os.Environ()
 	if javaHome != "" {
 		cmd.Env = append(cmd.Env, "JAVA_HOME="+javaHome)
 	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCheckMvnVersion, err)
+	}
 
 	// 使用 channel 实现超时控制
-	var output []byte
 	done := make(chan error, 1)
 	go func() {
-		var err error
-		output, err = cmd.Output()
-		done <- err
+		done <- cmd.Wait()
 	}()
 
-	// 等待命令执行完成或超时
+	// 等待命令执行完成、超时或被取消
+	timer := time.NewTimer(mvnVersionTimeout)
+	defer timer.Stop()
 	select {
 	case err := <-done:
 		if err != nil {
+			if javaNotFoundPattern.Match(stderr.Bytes()) || javaNotFoundPattern.Match(stdout.Bytes()) {
+				return "", fmt.Errorf("%w: %w: %v", ErrCheckMvnVersion, ErrJavaNotFound, err)
+			}
 			return "", fmt.Errorf("%w: %v", ErrCheckMvnVersion, err)
 		}
-	case <-time.After(8 * time.Second):
-		// 超时时强制结束进程
-		if err := cmd.Process.Kill(); err != nil {
+	case <-timer.C:
+		// 超时时强制结束整个进程组
+		if err := utils.KillProcessGroup(cmd.Process.Pid); err != nil {
 			return "", fmt.Errorf("无法终止超时的 Maven 进程: %w", err)
 		}
+		<-done
 		return "", fmt.Errorf("%w: 执行 Maven 版本命令超时", ErrCheckMvnVersion)
+	case <-ctx.Done():
+		if err := utils.KillProcessGroup(cmd.Process.Pid); err != nil {
+			logger.Warn("终止 Maven 进程组失败", zap.Int("pid", cmd.Process.Pid), zap.Error(err))
+		}
+		<-done
+		return "", ctx.Err()
 	}
 
-	return string(output), nil
+	return stdout.String(), nil
 }
 
-// checkMvnVersion 检查 Maven 的版本
+// checkMvnVersion 检查 Maven 的版本，同时返回 Maven 运行使用的 Java 版本
 // 对于 Linux 和 MacOS 系统，如果首次执行失败会尝试修改文件权限后重试
-func checkMvnVersion(mvnPath string, javaHome string) (string, error) {
-	output, err := executeMvnVersion(mvnPath, javaHome)
+func checkMvnVersion(ctx context.Context, logger *zap.Logger, mvnPath string, javaHome string) (string, string, error) {
+	output, err := executeMvnVersion(ctx, logger, mvnPath, javaHome)
 	if err != nil {
 		// 在 Unix 类系统上尝试修改文件权限后重试
-		if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
+		if ctx.Err() == nil && (runtime.GOOS == "linux" || runtime.GOOS == "darwin") {
 			_ = os.Chmod(mvnPath, 0755)
-			output, err = executeMvnVersion(mvnPath, javaHome)
+			logger.Debug("执行 mvn --version 失败，修改文件权限后重试", zap.String("path", mvnPath), zap.Error(err))
+			output, err = executeMvnVersion(ctx, logger, mvnPath, javaHome)
 		}
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 	}
 
 	// 解析版本号
 	ver := parseMvnVersion(output)
 	if ver == "" {
-		return "", fmt.Errorf("%w: 无法解析 Maven 版本信息", ErrCheckMvnVersion)
+		return "", "", fmt.Errorf("%w: 无法解析 Maven 版本信息", ErrCheckMvnVersion)
 	}
-	return ver, nil
+	return ver, parseMvnJavaVersion(output), nil
 }
 
 // parseMvnVersion 解析 Maven 命令输出中的版本号
@@ -213,6 +270,17 @@ func parseMvnVersion(input string) string {
 	return ""
 }
 
+// javaVersionPattern 匹配 mvn --version 输出中形如 "Java version: 17.0.2, vendor: ..." 的行
+var javaVersionPattern = regexp.MustCompile(`Java version: ([^,\s]+)`)
+
+// parseMvnJavaVersion 解析 Maven 命令输出中 Maven 所使用的 Java 版本
+func parseMvnJavaVersion(input string) string {
+	if m := javaVersionPattern.FindStringSubmatch(input); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
 // getMvnCommandOs 根据操作系统查找 Maven 命令的路径
 // 返回 Maven 可执行文件的绝对路径
 func getMvnCommandOs() string {
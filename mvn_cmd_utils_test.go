@@ -130,6 +130,20 @@ Java home: /usr/lib/jvm/java-11-openjdk-amd64`,
 	}
 }
 
+func TestParseMvnJavaVersion(t *testing.T) {
+	input := `Apache Maven 3.9.5 (57804ffe001d7215b5e7bcb531cf83df38f93546)
+Maven home: /opt/maven
+Java version: 17.0.8.1, vendor: Eclipse Adoptium, runtime: /opt/java/openjdk
+Default locale: en_US, platform encoding: UTF-8`
+
+	if got := parseMvnJavaVersion(input); got != "17.0.8.1" {
+		t.Errorf("parseMvnJavaVersion() = %v, want 17.0.8.1", got)
+	}
+	if got := parseMvnJavaVersion("Some random text"); got != "" {
+		t.Errorf("parseMvnJavaVersion() = %v, want empty", got)
+	}
+}
+
 func TestCheckMvnCommand(t *testing.T) {
 	// 保存原始缓存并在测试结束后恢复
 	originalCache := cachedMvnCommandResult
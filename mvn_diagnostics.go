@@ -0,0 +1,217 @@
+package pom_component_parsing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DiagnosticSeverity 表示 Maven 日志中诊断信息的级别
+type DiagnosticSeverity string
+
+const (
+	DiagnosticError   DiagnosticSeverity = "ERROR"   // [ERROR] 或 [FATAL] 行
+	DiagnosticWarning DiagnosticSeverity = "WARNING" // [WARNING] 行
+)
+
+// DiagnosticKind 表示 Maven 构建失败的原因类型
+type DiagnosticKind string
+
+const (
+	DiagnosticUnresolvableArtifact DiagnosticKind = "unresolvable-artifact" // 依赖工件无法解析
+	DiagnosticUnresolvableParent   DiagnosticKind = "unresolvable-parent"   // 父 POM 无法解析
+	DiagnosticPluginResolution     DiagnosticKind = "plugin-resolution"     // 插件或其依赖无法解析
+	DiagnosticExtension            DiagnosticKind = "extension"             // 构建扩展无法解析或加载
+	DiagnosticAuthentication       DiagnosticKind = "authentication"        // 仓库返回 401/403
+	DiagnosticOther                DiagnosticKind = "other"                 // 其他错误
+)
+
+// Diagnostic 是从 Maven 日志中解析出的一条诊断信息
+type Diagnostic struct {
+	Severity      DiagnosticSeverity `json:"severity"`
+	Kind          DiagnosticKind     `json:"kind"`
+	Message       string             `json:"message"`                  // 去掉级别前缀后的原始日志
+	Project       string             `json:"project,omitempty"`        // 出错的模块，如 "on project app" 中的 app，或父 POM 无法解析的模块坐标
+	Coordinate    string             `json:"coordinate,omitempty"`     // 无法解析的工件、父 POM、插件或扩展的坐标
+	Repository    string             `json:"repository,omitempty"`     // 仓库 id
+	RepositoryURL string             `json:"repository_url,omitempty"` // 仓库地址
+	StatusCode    int                `json:"status_code,omitempty"`    // 仓库返回的 HTTP 状态码
+}
+
+// String 返回诊断信息的简要描述
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("[%s] %s: %s", d.Severity, d.Kind, d.Message)
+}
+
+var (
+	// diagnosticLevelPattern 匹配日志行的级别前缀，Maven 在某些情况下会输出两层前缀，如 "[ERROR] [ERROR] ..."
+	diagnosticLevelPattern = regexp.MustCompile(`^(?:\[(?:ERROR|FATAL|WARNING)\]\s*)+`)
+	// ansiPattern 匹配非 batch 模式下 Maven 输出的颜色控制字符
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	// helpPattern 匹配错误信息末尾指向帮助链接的 " -> [Help 1]"，以及帮助链接行本身
+	helpPattern = regexp.MustCompile(`(?:\s*->\s*)?\[Help \d+\].*$`)
+
+	projectPattern       = regexp.MustCompile(`on project ([^\s:]+)`)
+	parentPattern        = regexp.MustCompile(`Non-resolvable parent POM for ([^\s:]+:[^\s:]+:[^\s:]+)`)
+	pluginPattern        = regexp.MustCompile(`Plugin (\S+) or one of its dependencies could not be resolved`)
+	pluginVersionPattern = regexp.MustCompile(`(?:resolving version for plugin|No plugin found for prefix) '([^']+)'`)
+	extensionPattern     = regexp.MustCompile(`(?:Extension|build extension: Plugin) (\S+) or one of its dependencies`)
+	artifactPatterns     = []*regexp.Regexp{
+		regexp.MustCompile(`Could not find artifact (\S+)`),
+		regexp.MustCompile(`Failure to find (\S+)`),
+		regexp.MustCompile(`Could not transfer artifact (\S+)`),
+		regexp.MustCompile(`artifacts? could not be resolved: ([^\s,]+)`),
+		regexp.MustCompile(`Failed to read artifact descriptor for (\S+)`),
+		regexp.MustCompile(`The POM for (\S+) is (?:missing|invalid)`),
+	}
+	repositoryPattern    = regexp.MustCompile(`(?:from/to|in) ([^\s(]+) \((https?://[^)\s]+)\)`)
+	repositoryURLPattern = regexp.MustCompile(`(?:in|for) (https?://[^\s,]+)`)
+	statusCodePattern    = regexp.MustCompile(`(?:status code: |status: |\()(401|403)\b`)
+	authPattern          = regexp.MustCompile(`(?i)\b(?:unauthorized|forbidden|not authorized|authentication failed)\b`)
+)
+
+// diagnosticBoilerplate 是 Maven 失败时固定输出的提示信息，不作为诊断
+var diagnosticBoilerplate = []string{
+	"To see the full stack trace of the errors",
+	"Re-run Maven using the -X switch",
+	"For more information about the errors and possible solutions",
+	"After correcting the problems, you can resume the build",
+	"mvn <args> -rf",
+	"Some problems were encountered while processing the POMs",
+	"The build could not read",
+	"BUILD FAILURE",
+}
+
+// ParseDiagnostic 解析一行 Maven 日志，返回其中的诊断信息。
+// 非 [ERROR]/[FATAL]/[WARNING] 行、固定的提示信息以及无法归类的警告返回 false
+func ParseDiagnostic(line string) (Diagnostic, bool) {
+	line = ansiPattern.ReplaceAllString(strings.TrimSpace(line), "")
+	prefix := diagnosticLevelPattern.FindString(line)
+	if prefix == "" {
+		return Diagnostic{}, false
+	}
+	severity := DiagnosticError
+	if strings.HasPrefix(prefix, "[WARNING]") {
+		severity = DiagnosticWarning
+	}
+
+	msg := strings.TrimSpace(helpPattern.ReplaceAllString(line[len(prefix):], ""))
+	if msg == "" {
+		return Diagnostic{}, false
+	}
+	for _, b := range diagnosticBoilerplate {
+		if strings.Contains(msg, b) {
+			return Diagnostic{}, false
+		}
+	}
+
+	d := Diagnostic{Severity: severity, Kind: DiagnosticOther, Message: msg}
+	if m := projectPattern.FindStringSubmatch(msg); m != nil {
+		d.Project = m[1]
+	}
+	if m := repositoryPattern.FindStringSubmatch(msg); m != nil {
+		d.Repository, d.RepositoryURL = m[1], m[2]
+	} else if m := repositoryURLPattern.FindStringSubmatch(msg); m != nil {
+		d.RepositoryURL = m[1]
+	}
+
+	// 按从具体到一般的顺序归类：父 POM、扩展、插件的错误信息中同样包含无法解析的工件
+	switch {
+	case parentPattern.MatchString(msg):
+		d.Kind = DiagnosticUnresolvableParent
+		d.Project = parentPattern.FindStringSubmatch(msg)[1]
+		d.Coordinate = diagnosticArtifact(msg)
+	case extensionPattern.MatchString(msg):
+		d.Kind = DiagnosticExtension
+		d.Coordinate = extensionPattern.FindStringSubmatch(msg)[1]
+	case pluginPattern.MatchString(msg):
+		d.Kind = DiagnosticPluginResolution
+		d.Coordinate = pluginPattern.FindStringSubmatch(msg)[1]
+	case pluginVersionPattern.MatchString(msg):
+		d.Kind = DiagnosticPluginResolution
+		d.Coordinate = pluginVersionPattern.FindStringSubmatch(msg)[1]
+	default:
+		if d.Coordinate = diagnosticArtifact(msg); d.Coordinate != "" {
+			d.Kind = DiagnosticUnresolvableArtifact
+		}
+	}
+
+	// 认证失败优先于其他类型，无论是哪个工件，用户需要修复的都是仓库的凭据
+	if m := statusCodePattern.FindStringSubmatch(msg); m != nil {
+		d.StatusCode, _ = strconv.Atoi(m[1])
+		d.Kind = DiagnosticAuthentication
+	} else if authPattern.MatchString(msg) {
+		d.Kind = DiagnosticAuthentication
+	}
+
+	// 无法归类的警告通常与构建失败无关，忽略
+	if d.Severity == DiagnosticWarning && d.Kind == DiagnosticOther {
+		return Diagnostic{}, false
+	}
+	return d, true
+}
+
+// diagnosticArtifact 返回日志中无法解析的工件坐标，没有时返回空字符串
+func diagnosticArtifact(msg string) string {
+	for _, p := range artifactPatterns {
+		if m := p.FindStringSubmatch(msg); m != nil {
+			return strings.TrimRight(m[1], ":,")
+		}
+	}
+	return ""
+}
+
+// ParseMavenLog 解析 Maven 的构建日志，返回去重后的诊断信息
+func ParseMavenLog(r io.Reader) ([]Diagnostic, error) {
+	var c diagnosticCollector
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		c.add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 Maven 日志失败: %w", err)
+	}
+	return c.Diagnostics(), nil
+}
+
+// diagnosticCollector 收集 Maven 日志中的诊断信息，可以被标准输出与标准错误输出并发调用
+type diagnosticCollector struct {
+	mu          sync.Mutex
+	diagnostics []Diagnostic
+	seen        map[string]bool
+}
+
+// add 解析一行日志，同一问题在日志中多次出现时只保留第一条
+func (c *diagnosticCollector) add(line string) {
+	d, ok := ParseDiagnostic(line)
+	if !ok {
+		return
+	}
+	key := d.Message
+	if d.Kind != DiagnosticOther {
+		key = fmt.Sprintf("%s|%s|%s|%s|%d", d.Kind, d.Coordinate, d.Project, d.Repository, d.StatusCode)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seen == nil {
+		c.seen = make(map[string]bool)
+	}
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+// Diagnostics 返回已收集的诊断信息
+func (c *diagnosticCollector) Diagnostics() []Diagnostic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Diagnostic(nil), c.diagnostics...)
+}
@@ -0,0 +1,190 @@
+package pom_component_parsing
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDiagnostic(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   Diagnostic
+		wantOk bool
+	}{
+		{
+			name: "依赖工件无法解析",
+			line: "[ERROR] Failed to execute goal on project app: Could not resolve dependencies for project com.example:app:jar:1.0: " +
+				"Could not find artifact com.foo:bar:jar:1.2 in central (https://repo.maven.apache.org/maven2) -> [Help 1]",
+			want: Diagnostic{
+				Kind:          DiagnosticUnresolvableArtifact,
+				Project:       "app",
+				Coordinate:    "com.foo:bar:jar:1.2",
+				Repository:    "central",
+				RepositoryURL: "https://repo.maven.apache.org/maven2",
+			},
+			wantOk: true,
+		},
+		{
+			name: "Maven 3.9 的依赖工件无法解析",
+			line: "[ERROR] Failed to execute goal on project app: Could not resolve dependencies for project com.example:app:jar:1.0: " +
+				"The following artifacts could not be resolved: com.foo:bar:jar:1.2 (absent): " +
+				"com.foo:bar:jar:1.2 was not found in https://nexus.example.com/repository/public during a previous attempt.",
+			want: Diagnostic{
+				Kind:          DiagnosticUnresolvableArtifact,
+				Project:       "app",
+				Coordinate:    "com.foo:bar:jar:1.2",
+				RepositoryURL: "https://nexus.example.com/repository/public",
+			},
+			wantOk: true,
+		},
+		{
+			name: "父POM无法解析",
+			line: "[FATAL] Non-resolvable parent POM for com.example:app:1.0: Could not find artifact com.example:parent:pom:1.0 " +
+				"in central (https://repo.maven.apache.org/maven2) and 'parent.relativePath' points at wrong local POM @ line 5, column 11",
+			want: Diagnostic{
+				Kind:          DiagnosticUnresolvableParent,
+				Project:       "com.example:app:1.0",
+				Coordinate:    "com.example:parent:pom:1.0",
+				Repository:    "central",
+				RepositoryURL: "https://repo.maven.apache.org/maven2",
+			},
+			wantOk: true,
+		},
+		{
+			name: "插件无法解析",
+			line: "[ERROR] Plugin com.github.ferstl:depgraph-maven-plugin:4.0.1 or one of its dependencies could not be resolved: " +
+				"Failed to read artifact descriptor for com.github.ferstl:depgraph-maven-plugin:jar:4.0.1",
+			want: Diagnostic{
+				Kind:       DiagnosticPluginResolution,
+				Coordinate: "com.github.ferstl:depgraph-maven-plugin:4.0.1",
+			},
+			wantOk: true,
+		},
+		{
+			name: "插件前缀无法解析",
+			line: "[ERROR] No plugin found for prefix 'depgraph' in the current project and in the plugin groups",
+			want: Diagnostic{
+				Kind:       DiagnosticPluginResolution,
+				Coordinate: "depgraph",
+			},
+			wantOk: true,
+		},
+		{
+			name: "构建扩展无法解析",
+			line: "[ERROR] Unresolveable build extension: Plugin com.example:build-ext:1.0 or one of its dependencies could not be resolved",
+			want: Diagnostic{
+				Kind:       DiagnosticExtension,
+				Coordinate: "com.example:build-ext:1.0",
+			},
+			wantOk: true,
+		},
+		{
+			name: "仓库认证失败",
+			line: "[ERROR] Failed to execute goal on project app: Could not resolve dependencies for project com.example:app:jar:1.0: " +
+				"Could not transfer artifact com.foo:bar:jar:1.2 from/to nexus (https://nexus.example.com/repository/private): " +
+				"status code: 401, reason phrase: Unauthorized (401) -> [Help 1]",
+			want: Diagnostic{
+				Kind:          DiagnosticAuthentication,
+				Project:       "app",
+				Coordinate:    "com.foo:bar:jar:1.2",
+				Repository:    "nexus",
+				RepositoryURL: "https://nexus.example.com/repository/private",
+				StatusCode:    401,
+			},
+			wantOk: true,
+		},
+		{
+			name:   "依赖POM缺失的警告",
+			line:   "[WARNING] The POM for com.foo:bar:jar:1.2 is missing, no dependency information available",
+			want:   Diagnostic{Severity: DiagnosticWarning, Kind: DiagnosticUnresolvableArtifact, Coordinate: "com.foo:bar:jar:1.2"},
+			wantOk: true,
+		},
+		{
+			name:   "带颜色的其他错误",
+			line:   "\x1b[1;31m[ERROR]\x1b[m Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin:3.11.0:compile",
+			want:   Diagnostic{Kind: DiagnosticOther},
+			wantOk: true,
+		},
+		{
+			name: "无法归类的警告",
+			line: "[WARNING] Using platform encoding (UTF-8 actually) to copy filtered resources",
+		},
+		{
+			name: "固定的提示信息",
+			line: "[ERROR] Re-run Maven using the -X switch to enable full debug logging.",
+		},
+		{
+			name: "普通日志",
+			line: "[INFO] BUILD FAILURE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseDiagnostic(tt.line)
+			if ok != tt.wantOk {
+				t.Fatalf("ParseDiagnostic() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if tt.want.Severity == "" {
+				tt.want.Severity = DiagnosticError
+			}
+			// Message 是去掉级别前缀后的日志，单独比较
+			if got.Message == "" || strings.HasPrefix(got.Message, "[") {
+				t.Errorf("ParseDiagnostic() Message = %q", got.Message)
+			}
+			got.Message = ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDiagnostic() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMavenLog(t *testing.T) {
+	log := `[INFO] Scanning for projects...
+[ERROR] [ERROR] Some problems were encountered while processing the POMs:
+[FATAL] Non-resolvable parent POM for com.example:app:1.0: Could not find artifact com.example:parent:pom:1.0 in central (https://repo.maven.apache.org/maven2) @ line 5, column 11
+ @
+[ERROR] The build could not read 1 project -> [Help 1]
+[ERROR]
+[ERROR]   The project com.example:app:1.0 (/tmp/app/pom.xml) has 1 error
+[ERROR]     Non-resolvable parent POM for com.example:app:1.0: The following artifacts could not be resolved: com.example:parent:pom:1.0 (absent): Could not find artifact com.example:parent:pom:1.0 in central (https://repo.maven.apache.org/maven2) @ line 5, column 11 -> [Help 2]
+[ERROR]
+[ERROR] To see the full stack trace of the errors, re-run Maven with the -e switch.
+`
+	got, err := ParseMavenLog(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("ParseMavenLog() error = %v", err)
+	}
+
+	want := []Diagnostic{
+		{Kind: DiagnosticUnresolvableParent, Coordinate: "com.example:parent:pom:1.0"},
+		{Kind: DiagnosticOther},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseMavenLog() = %v, want %d 条诊断", got, len(want))
+	}
+	for i := range want {
+		if got[i].Kind != want[i].Kind || got[i].Coordinate != want[i].Coordinate {
+			t.Errorf("ParseMavenLog()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMvnBuildError_Error(t *testing.T) {
+	err := &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{
+		{Severity: DiagnosticWarning, Kind: DiagnosticUnresolvableArtifact, Message: "The POM for com.foo:bar:jar:1.2 is missing"},
+		{Severity: DiagnosticError, Kind: DiagnosticAuthentication, Message: "status code: 401"},
+	}}
+	if got, want := err.Error(), "mvn 执行出错，退出码: 1: status code: 401"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := (&MvnBuildError{ExitCode: 2}).Error(), "mvn 执行出错，退出码: 2"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
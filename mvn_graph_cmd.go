@@ -2,79 +2,340 @@ package pom_component_parsing
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
-	"syscall"
 	"time"
 
+	"github.com/liwenson/pom_component_parsing/logpipe"
 	"github.com/liwenson/pom_component_parsing/utils"
+	"go.uber.org/zap"
 )
 
-// PluginGraphCmd 用于执行 com.github.ferstl:depgraph-maven-plugin:4.0.1:graph 命令的辅助结构体
+// DefaultDepgraphPluginVersion 是默认使用的 depgraph-maven-plugin 版本
+const DefaultDepgraphPluginVersion = "4.0.1"
+
+// DefaultMvnTimeout 是扫描依赖时 Maven 命令默认的超时时间
+const DefaultMvnTimeout = 120 * time.Second
+
+// DefaultMvnIdleModeTimeout 是设置了 IdleTimeout 时 Maven 命令默认的总超时时间。
+// 此时由空闲超时发现卡住的 Maven，总超时只用于兜底，因此比 DefaultMvnTimeout 宽松得多
+const DefaultMvnIdleModeTimeout = 30 * time.Minute
+
+// MvnCmdOptions 是执行 Maven 命令时的通用选项，零值与默认行为一致
+type MvnCmdOptions struct {
+	ExtraArgs       []string  // 追加到命令行末尾的额外参数
+	Offline         bool      // 是否以离线模式（-o）执行
+	SecureTransport bool      // 为 true 时不添加允许不安全 TLS 连接的参数
+	Stdout          io.Writer // 命令的标准输出，为空时使用 os.Stdout
+	Stderr          io.Writer // 命令的标准错误输出，为空时使用 os.Stderr
+	// IdleTimeout 大于 0 时，Maven 连续这么长时间没有输出任何一行就终止命令，
+	// 与总超时同时生效，使下载依赖较慢但仍有进展的构建不会被误杀
+	IdleTimeout time.Duration
+	// AllowPartial 为 true 时以 --fail-at-end 执行，部分模块构建失败时仍收集其余模块的结果，
+	// 扫描结果中只包含成功的模块，失败的模块记录在 *PartialResultError 中
+	AllowPartial bool
+	// Progress 不为空时接收 Maven 启动、模块依赖解析完成与下载文件等进度事件，由 Maven 的输出解析得到
+	Progress ProgressFunc
+}
+
+// args 在 goal 参数之后按选项追加传输、离线、profile 与额外参数
+func (o MvnCmdOptions) args(goalArgs []string, profiles []string) []string {
+	args := append([]string{}, goalArgs...)
+	if !o.SecureTransport {
+		args = append(args, insecureTransportArgs...)
+	}
+	if o.Offline {
+		args = append(args, "--offline")
+	}
+	if o.AllowPartial {
+		args = append(args, "--fail-at-end")
+	}
+	// 如果有指定配置文件，则添加 -P 参数
+	args = append(args, profileArgs(profiles)...)
+	return append(args, o.ExtraArgs...)
+}
+
+// PluginGraphCmd 用于执行 com.github.ferstl:depgraph-maven-plugin:graph 命令的辅助结构体
 type PluginGraphCmd struct {
-	Profiles     []string        // Maven 配置文件
-	Timeout      time.Duration   // 超时时间
-	ScanDir      string          // 扫描目录
-	MavenCmdInfo *MvnCommandInfo // Maven 命令信息
+	Profiles      []string        // Maven 配置文件
+	Timeout       time.Duration   // 超时时间
+	ScanDir       string          // 扫描目录
+	PluginVersion string          // depgraph-maven-plugin 的版本，为空时使用 DefaultDepgraphPluginVersion
+	MavenCmdInfo  *MvnCommandInfo // Maven 命令信息
+	Logger        *zap.Logger     // 日志记录器，为空时不输出日志
+	// OutputDir 不为空时，各模块的图文件写入 OutputDir/<groupId>/<artifactId>/<version>/dependency-graph.json，
+	// 为空时写入各模块的 target 目录
+	OutputDir     string
+	MvnCmdOptions // 通用的 Maven 命令选项
+}
+
+// insecureTransportArgs 配置 Maven 参数以允许不安全的 TLS 连接
+var insecureTransportArgs = []string{
+	"-Dmaven.wagon.http.ssl.ignore.validity.dates=true",
+	"-Dmaven.resolver.transport=wagon",
+	"-Dmaven.wagon.http.ssl.allowall=true",
+	"-Dmaven.wagon.http.ssl.insecure=true",
 }
 
-// RunC 执行 Maven 图形命令，并添加超时控制以防止进程无法释放
+// profileArgs 返回激活指定 profile 的 -P 参数，profiles 为空时返回 nil
+func profileArgs(profiles []string) []string {
+	if len(profiles) == 0 {
+		return nil
+	}
+	return []string{"-P", strings.Join(profiles, ",")}
+}
+
+// RunC 执行 Maven 图形命令，并添加超时控制以防止进程无法释放。需要中途取消时使用 RunContext
 func (m PluginGraphCmd) RunC() error {
+	return m.RunContext(context.Background())
+}
+
+// RunContext 与 RunC 相同，ctx 取消时终止整个 Maven 进程组
+func (m PluginGraphCmd) RunContext(ctx context.Context) error {
+	version := m.PluginVersion
+	if version == "" {
+		version = DefaultDepgraphPluginVersion
+	}
+
 	// 构建 Maven 命令参数
-	args := []string{"com.github.ferstl:depgraph-maven-plugin:4.0.1:graph", "-DgraphFormat=json"}
-	// 配置 Maven 参数以允许不安全的 TLS 连接
-	args = append(args,
-		"-Dmaven.wagon.http.ssl.ignore.validity.dates=true",
-		"-Dmaven.resolver.transport=wagon",
-		"-Dmaven.wagon.http.ssl.allowall=true",
-		"-Dmaven.wagon.http.ssl.insecure=true",
-	)
+	goalArgs := []string{"com.github.ferstl:depgraph-maven-plugin:" + version + ":graph", "-DgraphFormat=json"}
+	if m.OutputDir != "" {
+		// 表达式由 Maven 按各模块求值。groupId:artifactId 相同、version 不同的模块可以出现在同一个 reactor 中，
+		// 而 Maven 拒绝 groupId:artifactId:version 重复的 reactor，因此每个模块的输出目录不会相互覆盖
+		goalArgs = append(goalArgs, "-DoutputDirectory="+filepath.Join(m.OutputDir, "${project.groupId}", "${project.artifactId}", "${project.version}"))
+	}
+	args := m.MvnCmdOptions.args(goalArgs, m.Profiles)
 
-	// 如果有指定配置文件，则添加 -P 参数
-	if len(m.Profiles) > 0 {
-		args = append(args, "-P")
-		args = append(args, strings.Join(m.Profiles, ","))
+	return runMvnCommand(ctx, loggerOrNop(m.Logger), m.MavenCmdInfo, m.ScanDir, m.Timeout, args, m.MvnCmdOptions)
+}
+
+// ErrMvnTimeout 表示 Maven 命令执行超时
+var ErrMvnTimeout = errors.New("Maven 命令执行超时")
+
+// ErrMvnIdleTimeout 表示 Maven 命令超过 IdleTimeout 没有输出，errors.Is(err, ErrMvnTimeout) 同样成立
+var ErrMvnIdleTimeout = fmt.Errorf("Maven 命令长时间没有输出: %w", ErrMvnTimeout)
+
+// maxIdleCheckInterval 是检查 Maven 是否空闲的最大间隔
+const maxIdleCheckInterval = time.Second
+
+// outputWatchdog 将 Maven 的标准输出与标准错误输出经过 logpipe.Pipe，记录最后一行输出的时间，
+// 收集诊断信息并解析 reactor 的进度
+type outputWatchdog struct {
+	diagnosticCollector
+	reactor reactorProgress
+
+	start  time.Time
+	pipes  []*logpipe.Pipe
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// newOutputWatchdog 创建 outputWatchdog，输出仍然写入 stdout 与 stderr，每一行同时以 Debug 级别写入 logger，
+// progress 不为空时接收 reactor 的进度事件
+func newOutputWatchdog(logger *zap.Logger, progress ProgressFunc, stdout, stderr io.Writer) *outputWatchdog {
+	w := &outputWatchdog{start: time.Now(), reactor: reactorProgress{progress: progress}}
+	outPipe := logpipe.NewWithOption(logpipe.Option{Logger: logger, Prefix: "stdout", OnLine: w.line})
+	errPipe := logpipe.NewWithOption(logpipe.Option{Logger: logger, Prefix: "stderr", OnLine: w.line})
+	w.pipes = []*logpipe.Pipe{outPipe, errPipe}
+	w.stdout = io.MultiWriter(stdout, outPipe)
+	w.stderr = io.MultiWriter(stderr, errPipe)
+	return w
+}
+
+// line 处理 Maven 输出的一行
+func (w *outputWatchdog) line(line string) {
+	w.add(line)
+	w.reactor.add(line)
+}
+
+// idle 返回距最后一行输出（还没有输出时为启动时间）经过的时间
+func (w *outputWatchdog) idle() time.Duration {
+	last := w.start
+	for _, p := range w.pipes {
+		if t := p.LastLine(); t.After(last) {
+			last = t
+		}
 	}
+	return time.Since(last)
+}
 
-	// 获取 Maven 命令执行实例
-	cmd := m.MavenCmdInfo.Command(args...)
-	cmd.Dir = m.ScanDir
-	utils.SetPGid(cmd)
+// Close 关闭所有管道，返回后所有输出都已被处理
+func (w *outputWatchdog) Close() {
+	for _, p := range w.pipes {
+		_ = p.Close()
+	}
+}
 
-	// 将命令的标准输出和标准错误输出指向对应的输出流
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// idleCheckInterval 返回检查空闲的间隔，保证超时后最多延迟 idleTimeout 的四分之一被发现
+func idleCheckInterval(idleTimeout time.Duration) time.Duration {
+	interval := idleTimeout / 4
+	if interval > maxIdleCheckInterval {
+		interval = maxIdleCheckInterval
+	}
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	return interval
+}
 
-	// 打印启动命令的信息
-	log.Printf("开始执行命令: %s, 目录: %s\n", cmd.String(), cmd.Dir)
+// killGracePeriod 是超时后发送 SIGTERM 到强制杀死进程组之间等待的秒数
+const killGracePeriod = 5
 
-	// 创建上下文，以便可以在超时后取消命令执行
-	ctx := context.Background()
-	if m.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
-		defer cancel()
+// ProcessTerminatedError 表示 Maven 命令因超时或取消被终止，记录被终止的进程
+type ProcessTerminatedError struct {
+	Cause  error               // 终止的原因，为 ErrMvnTimeout 或 ctx 的错误
+	Killed []utils.ProcessInfo // 被终止的进程，包括 Maven 自身及其 fork 出的 JVM 等子进程
+}
+
+// Error 实现 error 接口
+func (e *ProcessTerminatedError) Error() string {
+	killed := make([]string, 0, len(e.Killed))
+	for _, p := range e.Killed {
+		killed = append(killed, p.String())
 	}
+	return fmt.Sprintf("Maven 命令被终止（%v），终止的进程: [%s]", e.Cause, strings.Join(killed, ", "))
+}
 
-	// 关联上下文到命令，以便在上下文取消时终止命令
-	cmd = exec.CommandContext(ctx, cmd.Path, cmd.Args[1:]...)
+// Unwrap 返回终止的原因，便于使用 errors.Is 判断超时或取消
+func (e *ProcessTerminatedError) Unwrap() error {
+	return e.Cause
+}
 
-	// 重新设置命令的工作目录与 PGid
-	cmd.Dir = m.ScanDir
+// ErrBuildFailure 表示 Maven 构建失败，*MvnBuildError 满足 errors.Is(err, ErrBuildFailure)
+var ErrBuildFailure = errors.New("Maven 构建失败")
+
+// MvnBuildError 表示 Maven 命令以非 0 退出码结束，Diagnostics 中是从构建日志中解析出的失败原因
+type MvnBuildError struct {
+	ExitCode    int          // Maven 的退出码
+	Diagnostics []Diagnostic // 构建日志中的 [ERROR]/[WARNING] 诊断信息
+	Err         error        // cmd.Wait 返回的错误
+}
+
+// Error 实现 error 接口，包含第一条错误诊断
+func (e *MvnBuildError) Error() string {
+	msg := fmt.Sprintf("mvn 执行出错，退出码: %d", e.ExitCode)
+	for _, d := range e.Diagnostics {
+		if d.Severity == DiagnosticError {
+			return msg + ": " + d.Message
+		}
+	}
+	return msg
+}
+
+// Unwrap 返回 cmd.Wait 返回的错误
+func (e *MvnBuildError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrBuildFailure) 成立
+func (e *MvnBuildError) Is(target error) bool {
+	return target == ErrBuildFailure
+}
+
+// moduleErrors 按 Diagnostic.Project 将构建失败拆分为各模块的错误，
+// 每个模块的 *MvnBuildError 只包含该模块的诊断信息，无法确定模块的诊断归入 Module 为空的错误
+func (e *MvnBuildError) moduleErrors() []ModuleError {
+	var modules []string
+	byModule := map[string][]Diagnostic{}
+	for _, d := range e.Diagnostics {
+		if d.Severity != DiagnosticError {
+			continue
+		}
+		if _, ok := byModule[d.Project]; !ok {
+			modules = append(modules, d.Project)
+		}
+		byModule[d.Project] = append(byModule[d.Project], d)
+	}
+	if len(modules) == 0 {
+		return []ModuleError{{Err: e}}
+	}
+
+	errs := make([]ModuleError, 0, len(modules))
+	for _, m := range modules {
+		errs = append(errs, ModuleError{Module: m, Err: &MvnBuildError{ExitCode: e.ExitCode, Diagnostics: byModule[m], Err: e.Err}})
+	}
+	return errs
+}
+
+// terminateProcessGroup 终止以 pid 为组长的整个进程组并等待组长进程退出，返回被终止的进程。
+// graceful 为 true 时先发送 SIGTERM，killGracePeriod 秒后仍有进程存在再发送 SIGKILL
+func terminateProcessGroup(logger *zap.Logger, pid int, graceful bool, done <-chan error) []utils.ProcessInfo {
+	killed, err := utils.ListProcessGroup(pid)
+	if err != nil {
+		logger.Warn("列出 Maven 进程组失败", zap.Int("pid", pid), zap.Error(err))
+		killed = []utils.ProcessInfo{{Pid: pid}}
+	}
+
+	if graceful {
+		err = utils.GracefullyKillGroup(pid, killGracePeriod)
+	} else {
+		err = utils.KillProcessGroup(pid)
+	}
+	if err != nil {
+		logger.Warn("终止 Maven 进程组失败", zap.Int("pid", pid), zap.Error(err))
+	}
+
+	// 等待组长进程退出，避免信号发送失败时永久阻塞
+	select {
+	case <-done:
+	case <-time.After(killGracePeriod * time.Second):
+		logger.Warn("等待 Maven 进程退出超时", zap.Int("pid", pid))
+	}
+	return killed
+}
+
+// runMvnCommand 在 dir 目录下执行 Maven 命令，timeout 大于 0 时超时后优雅地终止整个进程组，
+// opts.IdleTimeout 大于 0 时 Maven 空闲超时后同样优雅地终止，
+// ctx 取消时立即终止整个进程组（包括 Maven fork 出的 JVM 等子进程）
+func runMvnCommand(ctx context.Context, logger *zap.Logger, mvnCmdInfo *MvnCommandInfo, dir string, timeout time.Duration, args []string, opts MvnCmdOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	// 获取 Maven 命令执行实例，设置命令的工作目录与 PGid
+	cmd := mvnCmdInfo.Command(args...)
+	cmd.Dir = dir
 	utils.SetPGid(cmd)
 
-	// 将命令的标准输出和标准错误输出指向对应的输出流
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// 将命令的标准输出和标准错误输出经过 logpipe 指向对应的输出流，记录最后一行输出的时间
+	watchdog := newOutputWatchdog(logger, opts.Progress, stdout, stderr)
+	defer watchdog.Close()
+	cmd.Stdout = watchdog.stdout
+	cmd.Stderr = watchdog.stderr
+	// Maven 退出后，继承了输出管道的子进程不应使 Wait 一直阻塞
+	cmd.WaitDelay = killGracePeriod * time.Second
+
+	// 打印启动命令的信息
+	logger = logger.With(zap.String("dir", cmd.Dir))
+	logger.Info("开始执行 Maven 命令", zap.String("command", cmd.String()))
+	start := time.Now()
+
+	// 创建超时上下文，以便可以在超时后终止命令执行
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
 	// 启动命令
 	if err := cmd.Start(); err != nil {
-		log.Printf("启动 Maven 命令失败: %s\n", err.Error())
+		logger.Error("启动 Maven 命令失败", zap.Error(err))
 		return fmt.Errorf("启动 Maven 命令失败: %w", err)
 	}
+	watchdog.reactor.begin()
 
 	// 通道用于接收命令执行完成的错误信息
 	done := make(chan error, 1)
@@ -82,51 +343,71 @@ func (m PluginGraphCmd) RunC() error {
 		done <- cmd.Wait()
 	}()
 
-	select {
-	case <-ctx.Done():
-		// 上下文超时，尝试杀死进程
-		if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
-			log.Printf("发送SIGTERM信号失败: %s\n", err.Error())
-			return fmt.Errorf("发送SIGTERM信号失败: %w", err)
-		}
-		// 等待进程退出
+	// 空闲检查的定时器，未设置 IdleTimeout 时 idleTick 为 nil，不会触发
+	var idleTick <-chan time.Time
+	if opts.IdleTimeout > 0 {
+		ticker := time.NewTicker(idleCheckInterval(opts.IdleTimeout))
+		defer ticker.Stop()
+		idleTick = ticker.C
+	}
+
+	for {
 		select {
-		case <-done:
-			log.Println("Maven 命令已超时并被终止")
-			return fmt.Errorf("Maven 命令超时并被终止")
-		case <-time.After(5 * time.Second):
-			// 如果进程在5秒内没有响应SIGTERM，则强制杀死
-			if err := cmd.Process.Kill(); err != nil {
-				log.Printf("强制杀死进程失败: %s\n", err.Error())
-				return fmt.Errorf("强制杀死进程失败: %w", err)
+		case <-idleTick:
+			if watchdog.idle() < opts.IdleTimeout {
+				continue
 			}
-			log.Println("Maven 命令被强制杀死")
-			return fmt.Errorf("Maven 命令被强制杀死")
-		}
-	case err := <-done:
-		// 命令执行完成
-		if err != nil {
-			// 命令执行出错，获取退出码
-			exitError, ok := err.(*exec.ExitError)
-			if ok {
-				exitCode := exitError.ExitCode()
-				log.Printf("执行 mvn 时发生错误: %s. 退出码: %d\n", err.Error(), exitCode)
-				return fmt.Errorf("mvn 执行出错: %w", err)
+			// Maven 长时间没有输出，与超时一样优雅地终止整个进程组
+			err := &ProcessTerminatedError{Cause: ErrMvnIdleTimeout, Killed: terminateProcessGroup(logger, cmd.Process.Pid, true, done)}
+			logger.Error("Maven 命令长时间没有输出，已终止", zap.Duration("idle_timeout", opts.IdleTimeout), zap.Duration("duration", time.Since(start)), zap.Error(err))
+			return err
+		case <-runCtx.Done():
+			// 超时时先发送 SIGTERM 给整个进程组，等待后仍未退出再强制杀死；调用方取消时立即杀死整个进程组
+			cause, graceful := error(ErrMvnTimeout), true
+			if ctx.Err() != nil {
+				cause, graceful = ctx.Err(), false
 			}
-			// 其他类型的错误
-			log.Printf("执行 mvn 时发生未知错误: %s\n", err.Error())
-			return fmt.Errorf("mvn 执行出错: %w", err)
+			err := &ProcessTerminatedError{Cause: cause, Killed: terminateProcessGroup(logger, cmd.Process.Pid, graceful, done)}
+			logger.Error("Maven 命令被终止", zap.Duration("duration", time.Since(start)), zap.Error(err))
+			return err
+		case err := <-done:
+			// 等待剩余的输出处理完成后再读取诊断信息
+			watchdog.Close()
+			return mvnExitError(logger.With(zap.Duration("duration", time.Since(start))), cmd, err, watchdog.Diagnostics())
 		}
+	}
+}
 
-		// 获取退出码
-		exitCode := cmd.ProcessState.ExitCode()
-		if exitCode != 0 {
-			log.Printf("mvn 执行出错。退出码: %d\n", exitCode)
-			return fmt.Errorf("mvn 执行出错，退出码: %d", exitCode)
+// mvnExitError 根据 cmd.Wait 的结果返回 Maven 命令的错误，命令成功时返回 nil，
+// Maven 以非 0 退出码结束时返回包含诊断信息的 *MvnBuildError
+func mvnExitError(logger *zap.Logger, cmd *exec.Cmd, err error, diagnostics []Diagnostic) error {
+	// 子进程持有输出管道导致等待超时，Maven 自身已经退出，按退出码判断结果
+	if errors.Is(err, exec.ErrWaitDelay) {
+		logger.Warn("等待 Maven 输出关闭超时", zap.Error(err))
+		err = nil
+	}
+	if err != nil {
+		// 命令执行出错，获取退出码
+		exitError, ok := err.(*exec.ExitError)
+		if ok {
+			buildErr := &MvnBuildError{ExitCode: exitError.ExitCode(), Diagnostics: diagnostics, Err: err}
+			logger.Error("Maven 构建失败", zap.Int("exit_code", buildErr.ExitCode), zap.Stringers("diagnostics", buildErr.Diagnostics))
+			return buildErr
 		}
+		// 其他类型的错误
+		logger.Error("执行 Maven 命令时发生未知错误", zap.Error(err))
+		return fmt.Errorf("mvn 执行出错: %w", err)
+	}
 
-		// 命令成功完成
-		log.Println("Maven 命令执行成功")
-		return nil
+	// 获取退出码
+	exitCode := cmd.ProcessState.ExitCode()
+	if exitCode != 0 {
+		buildErr := &MvnBuildError{ExitCode: exitCode, Diagnostics: diagnostics}
+		logger.Error("Maven 构建失败", zap.Int("exit_code", exitCode), zap.Stringers("diagnostics", buildErr.Diagnostics))
+		return buildErr
 	}
+
+	// 命令成功完成
+	logger.Info("Maven 命令执行成功")
+	return nil
 }
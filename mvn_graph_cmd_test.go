@@ -0,0 +1,417 @@
+//go:build !windows
+
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liwenson/pom_component_parsing/utils"
+	"go.uber.org/zap/zaptest"
+)
+
+// writeFakeMvn 在临时目录中写入模拟 mvn 的 shell 脚本，返回对应的 MvnCommandInfo
+func writeFakeMvn(t *testing.T, dir, script string) *MvnCommandInfo {
+	t.Helper()
+	path := writeTestFile(t, dir, "mvn", "#!/bin/sh\n"+script)
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &MvnCommandInfo{Path: path}
+}
+
+func TestRunMvnCommand_Cancel(t *testing.T) {
+	dir := t.TempDir()
+	// 模拟 Maven fork 出的子进程：后台 sleep 并记录其 pid
+	info := writeFakeMvn(t, dir, "sleep 30 &\necho $! > child.pid\nwait\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// 等待子进程启动并写入 pid 后取消，重定向会先创建空文件
+		for i := 0; i < 100; i++ {
+			if data, err := os.ReadFile(filepath.Join(dir, "child.pid")); err == nil && strings.HasSuffix(string(data), "\n") {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		cancel()
+	}()
+
+	start := time.Now()
+	err := runMvnCommand(ctx, zaptest.NewLogger(t), info, dir, time.Minute, nil, MvnCmdOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("runMvnCommand() error = %v, want %v", err, context.Canceled)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("取消后没有及时返回")
+	}
+
+	assertProcessKilled(t, err, readChildPid(t, dir))
+}
+
+// readChildPid 读取模拟脚本记录的子进程 pid
+func readChildPid(t *testing.T, dir string) int {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "child.pid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pid
+}
+
+// assertProcessKilled 断言进程已被终止，并且出现在 ProcessTerminatedError 的 Killed 中
+func assertProcessKilled(t *testing.T, err error, pid int) {
+	t.Helper()
+	var terminated *ProcessTerminatedError
+	if !errors.As(err, &terminated) {
+		t.Fatalf("error = %v, want *ProcessTerminatedError", err)
+	}
+	reported := false
+	for _, p := range terminated.Killed {
+		reported = reported || p.Pid == pid
+	}
+	if !reported {
+		t.Errorf("Killed = %v, 缺少子进程 %d", terminated.Killed, pid)
+	}
+
+	for i := 0; i < 100 && utils.IsProcessExists(pid); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if utils.IsProcessExists(pid) {
+		t.Errorf("子进程 %d 没有被终止", pid)
+	}
+}
+
+func TestRunMvnCommand_Timeout(t *testing.T) {
+	dir := t.TempDir()
+	info := writeFakeMvn(t, dir, "sleep 30 &\necho $! > child.pid\nwait\n")
+
+	start := time.Now()
+	err := runMvnCommand(context.Background(), zaptest.NewLogger(t), info, dir, 500*time.Millisecond, nil, MvnCmdOptions{})
+	if !errors.Is(err, ErrMvnTimeout) {
+		t.Fatalf("runMvnCommand() error = %v, want %v", err, ErrMvnTimeout)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("超时后没有及时返回")
+	}
+	assertProcessKilled(t, err, readChildPid(t, dir))
+}
+
+func TestRunMvnCommand_IdleTimeout(t *testing.T) {
+	t.Run("持续输出时不触发空闲超时", func(t *testing.T) {
+		dir := t.TempDir()
+		// 总耗时超过 IdleTimeout，但每行输出的间隔都小于 IdleTimeout
+		info := writeFakeMvn(t, dir, "for i in 1 2 3 4 5 6; do echo \"Downloading $i\"; sleep 0.2; done\n")
+
+		var stdout strings.Builder
+		err := runMvnCommand(context.Background(), zaptest.NewLogger(t), info, dir, time.Minute, nil, MvnCmdOptions{IdleTimeout: 800 * time.Millisecond, Stdout: &stdout})
+		if err != nil {
+			t.Fatalf("runMvnCommand() error = %v", err)
+		}
+		if !strings.Contains(stdout.String(), "Downloading 6") {
+			t.Errorf("Maven 的输出没有写入 Stdout: %q", stdout.String())
+		}
+	})
+
+	t.Run("超过64KB的行之后继续输出时不触发空闲超时", func(t *testing.T) {
+		dir := t.TempDir()
+		info := writeFakeMvn(t, dir, "head -c 102400 /dev/zero | tr '\\0' a\necho\n"+
+			"for i in 1 2 3 4 5 6; do echo \"[INFO] Downloading from central: https://repo/$i.jar\"; sleep 0.2; done\n")
+
+		var lines []string
+		opts := MvnCmdOptions{IdleTimeout: 800 * time.Millisecond, Stdout: io.Discard, Progress: func(e ProgressEvent) {
+			if e.Phase == ProgressArtifactDownloading {
+				lines = append(lines, e.Artifact)
+			}
+		}}
+		if err := runMvnCommand(context.Background(), zaptest.NewLogger(t), info, dir, time.Minute, nil, opts); err != nil {
+			t.Fatalf("runMvnCommand() error = %v", err)
+		}
+		// 超长行之后的输出仍然会被解析
+		if len(lines) != 6 {
+			t.Errorf("下载事件 = %v, want 6 个", lines)
+		}
+	})
+
+	t.Run("没有输出时终止进程组", func(t *testing.T) {
+		dir := t.TempDir()
+		info := writeFakeMvn(t, dir, "echo started\nsleep 30 &\necho $! > child.pid\nwait\n")
+
+		start := time.Now()
+		err := runMvnCommand(context.Background(), zaptest.NewLogger(t), info, dir, time.Minute, nil, MvnCmdOptions{IdleTimeout: 500 * time.Millisecond, Stdout: io.Discard})
+		if !errors.Is(err, ErrMvnIdleTimeout) || !errors.Is(err, ErrMvnTimeout) {
+			t.Fatalf("runMvnCommand() error = %v, want %v", err, ErrMvnIdleTimeout)
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Errorf("空闲超时后没有及时返回")
+		}
+		assertProcessKilled(t, err, readChildPid(t, dir))
+	})
+}
+
+func TestRunMvnCommand_BuildFailure(t *testing.T) {
+	dir := t.TempDir()
+	info := writeFakeMvn(t, dir, "echo '[INFO] BUILD FAILURE'\n"+
+		"echo '[ERROR] Plugin com.github.ferstl:depgraph-maven-plugin:4.0.1 or one of its dependencies could not be resolved' >&2\n"+
+		"exit 1\n")
+
+	err := runMvnCommand(context.Background(), zaptest.NewLogger(t), info, dir, time.Minute, nil, MvnCmdOptions{Stdout: io.Discard, Stderr: io.Discard})
+	var buildErr *MvnBuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("runMvnCommand() error = %v, want *MvnBuildError", err)
+	}
+	if buildErr.ExitCode != 1 {
+		t.Errorf("ExitCode = %d, want 1", buildErr.ExitCode)
+	}
+	if len(buildErr.Diagnostics) != 1 || buildErr.Diagnostics[0].Kind != DiagnosticPluginResolution {
+		t.Errorf("Diagnostics = %v", buildErr.Diagnostics)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("MvnBuildError 应包装 *exec.ExitError")
+	}
+}
+
+func TestRunMvnCommand_Progress(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "reactor.log", mavenReactorLog)
+	info := writeFakeMvn(t, dir, "cat reactor.log\n")
+
+	var phases []ProgressPhase
+	opts := MvnCmdOptions{Stdout: io.Discard, Stderr: io.Discard, Progress: func(e ProgressEvent) { phases = append(phases, e.Phase) }}
+	if err := runMvnCommand(context.Background(), zaptest.NewLogger(t), info, dir, time.Minute, nil, opts); err != nil {
+		t.Fatalf("runMvnCommand() error = %v", err)
+	}
+
+	want := []ProgressPhase{ProgressMavenStarted, ProgressArtifactDownloading, ProgressModuleResolved, ProgressArtifactDownloading, ProgressModuleResolved}
+	if !reflect.DeepEqual(phases, want) {
+		t.Errorf("阶段 = %v, want %v", phases, want)
+	}
+}
+
+func TestScanDepsByPluginGraphCmd_OutputDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>app</module>
+    <module>lib</module>
+    <module>lib-legacy</module>
+  </modules>
+  <properties>
+    <libs.group>com.example.libs</libs.group>
+  </properties>
+</project>`)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+	// groupId 中的属性定义在父 POM 中，version 由命令行的 -D 参数指定；
+	// lib-legacy 与 lib 的 groupId:artifactId 相同，只能按 version 区分
+	libPom := `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <groupId>${libs.group}</groupId>
+  <artifactId>lib</artifactId>
+  <version>%s</version>
+</project>`
+	writeTestFile(t, dir, "lib/pom.xml", fmt.Sprintf(libPom, "${lib.version}"))
+	writeTestFile(t, dir, "lib-legacy/pom.xml", fmt.Sprintf(libPom, "1.0.0"))
+	// 上次扫描残留、提交到仓库以及 node_modules 中的图文件都不应被读取
+	stale := `{"artifacts":[{"groupId":"com.example","artifactId":"stale","version":"0.1"}],"dependencies":[]}`
+	writeTestFile(t, dir, "app/target/dependency-graph.json", stale)
+	writeTestFile(t, dir, "docs/dependency-graph.json", stale)
+	writeTestFile(t, dir, "web/node_modules/pkg/dependency-graph.json", stale)
+
+	// 模拟 depgraph 插件：按各模块的坐标替换 -DoutputDirectory 中的表达式并写入图文件，
+	// ghost 不属于 POM 解析器得到的任何模块
+	info := writeFakeMvn(t, dir, `for arg in "$@"; do
+  case "$arg" in -DoutputDirectory=*) out="${arg#-DoutputDirectory=}";; esac
+done
+echo "$out" > output.dir
+for m in com.example:parent:1.0.0 com.example:app:1.0.0 com.example.libs:lib:2.0.0 com.example.libs:lib:1.0.0 com.example:ghost:1.0.0; do
+  g="${m%%:*}"
+  v="${m##*:}"
+  a="${m#*:}"
+  a="${a%:*}"
+  d=$(echo "$out" | sed "s|\${project.groupId}/\${project.artifactId}/\${project.version}|$g/$a/$v|")
+  mkdir -p "$d"
+  printf '{"artifacts":[{"groupId":"%s","artifactId":"%s","version":"%s","types":["jar"]}],"dependencies":[]}' "$g" "$a" "$v" > "$d/dependency-graph.json"
+done
+`)
+
+	deps, err := scanDepsByPluginGraphCmd(context.Background(), PluginGraphCmd{
+		Timeout:       time.Minute,
+		ScanDir:       dir,
+		MavenCmdInfo:  info,
+		Logger:        zaptest.NewLogger(t),
+		MvnCmdOptions: MvnCmdOptions{Stdout: io.Discard, Stderr: io.Discard, ExtraArgs: []string{"-Dlib.version=2.0.0"}},
+	})
+	if err != nil {
+		t.Fatalf("scanDepsByPluginGraphCmd() error = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, e := range deps.ListAllEntries() {
+		got[e.coordinate.ArtifactId+":"+e.coordinate.Version] = e.relativePath
+	}
+	// 无法对应到模块的图文件仍然保留，模块路径为项目根目录的 pom.xml
+	want := map[string]string{
+		"parent:1.0.0": "pom.xml",
+		"app:1.0.0":    filepath.Join("app", "pom.xml"),
+		"lib:2.0.0":    filepath.Join("lib", "pom.xml"),
+		"lib:1.0.0":    filepath.Join("lib-legacy", "pom.xml"),
+		"ghost:1.0.0":  "pom.xml",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("模块 = %v, want %v", got, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "output.dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Dir(filepath.Dir(filepath.Dir(strings.TrimSpace(string(data)))))
+	if strings.HasPrefix(outputDir, dir) {
+		t.Errorf("图文件输出目录 %s 不应位于项目目录中", outputDir)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("扫描结束后应删除图文件输出目录 %s, err = %v", outputDir, err)
+	}
+}
+
+func TestDepgraphScanner_ModuleProfilesNotForced(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>a</module>
+    <module>b</module>
+  </modules>
+</project>`)
+	// 两个模块声明了同名的 profile，只有 a 中的激活条件满足
+	profileModule := `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>%s</artifactId>
+  <profiles>
+    <profile>
+      <id>extra</id>
+      <activation><file><exists>marker</exists></file></activation>
+    </profile>
+  </profiles>
+</project>`
+	writeTestFile(t, dir, "a/pom.xml", fmt.Sprintf(profileModule, "a"))
+	writeTestFile(t, dir, "a/marker", "")
+	writeTestFile(t, dir, "b/pom.xml", fmt.Sprintf(profileModule, "b"))
+	info := writeFakeMvn(t, dir, "echo \"$@\" > args.txt\n")
+
+	var discovered []string
+	s := DepgraphScanner{MvnScanOptions: MvnScanOptions{
+		MavenCmdInfo: info,
+		Logger:       zaptest.NewLogger(t),
+		MvnCmdOptions: MvnCmdOptions{Stdout: io.Discard, Stderr: io.Discard, Progress: func(e ProgressEvent) {
+			if e.Phase == ProgressProfileDiscovery {
+				discovered = e.Profiles
+			}
+		}},
+	}}
+	// 假的 mvn 不生成图文件，只检查传给 Maven 的参数
+	_, _ = s.Scan(context.Background(), dir)
+
+	if !reflect.DeepEqual(discovered, []string{"extra"}) {
+		t.Errorf("报告的激活 profile = %v, want [extra]", discovered)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -P extra 会在模块 b 中强制激活条件不满足的 profile
+	for _, arg := range strings.Fields(string(data)) {
+		if arg == "-P" {
+			t.Errorf("没有显式指定 profile 时不应传递 -P: %s", data)
+		}
+	}
+}
+
+func TestCheckMvnVersion_JavaNotFound(t *testing.T) {
+	dir := t.TempDir()
+	info := writeFakeMvn(t, dir, "echo 'The JAVA_HOME environment variable is not defined correctly,' >&2\nexit 1\n")
+
+	_, _, err := checkMvnVersion(context.Background(), zaptest.NewLogger(t), info.Path, "")
+	if !errors.Is(err, ErrJavaNotFound) || !errors.Is(err, ErrCheckMvnVersion) {
+		t.Errorf("checkMvnVersion() error = %v, want %v", err, ErrJavaNotFound)
+	}
+}
+
+func TestRunMvnCommand_Canceled(t *testing.T) {
+	dir := t.TempDir()
+	info := writeFakeMvn(t, dir, "touch started\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runMvnCommand(ctx, zaptest.NewLogger(t), info, dir, 0, nil, MvnCmdOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("runMvnCommand() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "started")); err == nil {
+		t.Errorf("已取消的 context 不应启动 Maven")
+	}
+}
+
+func TestFindResultFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"target/dependency-graph.json",
+		"app/target/dependency-graph.json",
+		"web/node_modules/pkg/dependency-graph.json",
+		".git/dependency-graph.json",
+	} {
+		writeTestFile(t, dir, name, "{}")
+	}
+
+	got, err := findResultFiles(context.Background(), zaptest.NewLogger(t), dir, "dependency-graph.json")
+	if err != nil {
+		t.Fatalf("findResultFiles() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "app", "target", "dependency-graph.json"), filepath.Join(dir, "target", "dependency-graph.json")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findResultFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestFindResultFiles_Cancel(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "app/target/dependency-graph.json", "{}")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := findResultFiles(ctx, zaptest.NewLogger(t), dir, "dependency-graph.json"); !errors.Is(err, context.Canceled) {
+		t.Errorf("findResultFiles() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := collectPluginResultFile(ctx, zaptest.NewLogger(t), nil, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("collectPluginResultFile() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,64 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DependencyTreeOutputType 表示 maven-dependency-plugin:tree 的输出格式
+type DependencyTreeOutputType string
+
+const (
+	DependencyTreeText DependencyTreeOutputType = "text" // mvn dependency:tree 默认的文本树
+	DependencyTreeTGF  DependencyTreeOutputType = "tgf"  // Trivial Graph Format
+	DependencyTreeDOT  DependencyTreeOutputType = "dot"  // Graphviz DOT
+)
+
+// dependencyTreeFileName 是 dependency:tree 结果文件的文件名（不含扩展名），文件位于各模块的 target 目录下
+const dependencyTreeFileName = "dependency-tree"
+
+// FileName 返回该输出格式对应的结果文件名
+func (t DependencyTreeOutputType) FileName() string {
+	ext := string(t)
+	if t == DependencyTreeText || t == "" {
+		ext = "txt"
+	}
+	return dependencyTreeFileName + "." + ext
+}
+
+// DependencyTreeCmd 用于执行 org.apache.maven.plugins:maven-dependency-plugin:tree 命令的辅助结构体
+type DependencyTreeCmd struct {
+	Profiles      []string                 // Maven 配置文件
+	Timeout       time.Duration            // 超时时间
+	ScanDir       string                   // 扫描目录
+	OutputType    DependencyTreeOutputType // 输出格式，为空时使用 text
+	MavenCmdInfo  *MvnCommandInfo          // Maven 命令信息
+	Logger        *zap.Logger              // 日志记录器，为空时不输出日志
+	MvnCmdOptions                          // 通用的 Maven 命令选项
+}
+
+// RunC 执行 dependency:tree 命令，每个模块的结果写入各自 target 目录下的结果文件
+func (m DependencyTreeCmd) RunC() error {
+	return m.RunContext(context.Background())
+}
+
+// RunContext 与 RunC 相同，ctx 取消时终止整个 Maven 进程组
+func (m DependencyTreeCmd) RunContext(ctx context.Context) error {
+	outputType := m.OutputType
+	if outputType == "" {
+		outputType = DependencyTreeText
+	}
+
+	// 构建 Maven 命令参数，outputFile 为相对路径时 Maven 按各模块的目录解析
+	args := m.MvnCmdOptions.args([]string{
+		"org.apache.maven.plugins:maven-dependency-plugin:3.6.1:tree",
+		"-DoutputType=" + string(outputType),
+		"-DoutputFile=" + filepath.Join("target", outputType.FileName()),
+		"-DappendOutput=false",
+	}, m.Profiles)
+
+	return runMvnCommand(ctx, loggerOrNop(m.Logger), m.MavenCmdInfo, m.ScanDir, m.Timeout, args, m.MvnCmdOptions)
+}
@@ -6,7 +6,8 @@ import (
 	"sync"
 
 	"fmt"
-	"log"
+
+	"go.uber.org/zap"
 )
 
 // PluginGraphOutput 表示 Maven 依赖图的结构，从 dependency-graph.json 文件中读取
@@ -14,15 +15,29 @@ type PluginGraphOutput struct {
 	GraphName    string           `json:"graphName"`    // 图的名称
 	Artifacts    []Artifact       `json:"artifacts"`    // 构成图的各个工件
 	Dependencies []DependencyEdge `json:"dependencies"` // 工件之间的依赖关系
+	Logger       *zap.Logger      `json:"-"`            // 日志记录器，为空时不输出日志
 }
 
 // Artifact 表示单个 Maven 工件的信息
 type Artifact struct {
-	GroupId    string   `json:"groupId"`    // 组ID
-	ArtifactId string   `json:"artifactId"` // 工件ID
-	Optional   bool     `json:"optional"`   // 是否为可选依赖
-	Scopes     []string `json:"scopes"`     // 作用域
-	Version    string   `json:"version"`    // 版本
+	GroupId     string   `json:"groupId"`     // 组ID
+	ArtifactId  string   `json:"artifactId"`  // 工件ID
+	Optional    bool     `json:"optional"`    // 是否为可选依赖
+	Scopes      []string `json:"scopes"`      // 作用域
+	Version     string   `json:"version"`     // 版本
+	Types       []string `json:"types"`       // 工件类型，如 jar、test-jar
+	Classifiers []string `json:"classifiers"` // 分类器，如 linux-x86_64
+}
+
+// Coordinate 返回工件的坐标，类型与分类器取第一个值
+func (a Artifact) Coordinate() Coordinate {
+	return Coordinate{
+		GroupId:    a.GroupId,
+		ArtifactId: a.ArtifactId,
+		Version:    a.Version,
+		Type:       getFirstScope(a.Types),
+		Classifier: getFirstScope(a.Classifiers),
+	}
 }
 
 // DependencyEdge 表示工件之间的依赖关系
@@ -43,6 +58,7 @@ func (d *PluginGraphOutput) ReadFromFile(path string) error {
 		return fmt.Errorf("解析依赖图文件失败: %w", err)
 	}
 
+	graph.Logger = d.Logger
 	*d = graph
 	return nil
 }
@@ -69,19 +85,15 @@ func (d *PluginGraphOutput) buildDependencyTree(id int, visited []bool, edges ma
 
 	artifact := d.Artifacts[id]
 	dependency := &Dependency{
-		Coordinate: Coordinate{
-			GroupId:    artifact.GroupId,
-			ArtifactId: artifact.ArtifactId,
-			Version:    artifact.Version,
-		},
-		Scope:    getFirstScope(artifact.Scopes),
-		Children: []Dependency{},
+		Coordinate: artifact.Coordinate(),
+		Scope:      getFirstScope(artifact.Scopes),
+		Children:   []Dependency{},
 	}
 
 	for _, toID := range edges[id] {
 		child, err := d.buildDependencyTree(toID, visited, edges)
 		if err != nil {
-			log.Printf("构建子依赖时出错: %v", err)
+			loggerOrNop(d.Logger).Warn("构建子依赖时出错", zap.Error(err))
 			continue
 		}
 		if child != nil {
@@ -136,13 +148,13 @@ func (d *PluginGraphOutput) findRootNode() (int, error) {
 	}
 
 	if len(roots) > 1 {
-		log.Printf("警告: 依赖图有多个根节点: %v", roots)
+		loggerOrNop(d.Logger).Warn("依赖图有多个根节点", zap.Ints("roots", roots))
 	}
 
 	return roots[0], nil
 }
 
-// getFirstScope 获取作用域切片中的第一个元素，如果切片为空则返回空字符串
+// getFirstScope 获取切片（如作用域、类型）中的第一个元素，如果切片为空则返回空字符串
 func getFirstScope(scopes []string) string {
 	if len(scopes) > 0 {
 		return scopes[0]
@@ -1,82 +1,327 @@
 package pom_component_parsing
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/fs"
-	"log"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/vifraa/gopom"
+	"go.uber.org/zap"
 )
 
 // ScanDepsByPluginCommand 使用 Maven 插件命令扫描依赖关系。
-// 该函数不再使用上下文，并使用默认日志打印日志信息。
+// 该函数不使用上下文，也不输出日志，无法中途取消。需要取消扫描（如请求中断时终止 Maven 进程组）或输出日志时，
+// 使用 DepgraphScanner.Scan 或 PluginGraphCmd.RunContext，并设置 Logger。
+// 不传递 -P 参数，由 Maven 在每个模块中分别评估 profile 的激活条件。
 func ScanDepsByPluginCommand(projectDir string, mvnCmdInfo *MvnCommandInfo) (*DepsMap, error) {
-	// 查找项目的 Pom 配置文件中的 profiles
-	profiles, err := findPomProfiles(filepath.Join(projectDir, "pom.xml"))
+	return ScanDepsByPluginCommandWithProfiles(projectDir, mvnCmdInfo, nil)
+}
+
+// activeProfiles 评估项目各模块与 settings.xml 中 profiles 的激活条件，返回处于激活状态的 profile。
+// 各模块的评估结果合并在一起，只用于记录与报告：-P 会在所有模块中按 ID 激活 profile，
+// 把某个模块中激活的 profile 传给 -P 会强制激活其他模块中条件不满足的同名 profile
+func activeProfiles(logger *zap.Logger, projectDir string, mvnCmdInfo *MvnCommandInfo) []string {
+	start := time.Now()
+	activation := NewActivationContext(mvnCmdInfo.JavaVersion)
+	profiles, err := findActiveProfiles(logger, projectDir, mvnCmdInfo.UserSettingsPath, activation)
 	if err != nil {
 		// 打印错误信息
-		log.Printf("查找 Pom profiles 时出错: %v\n", err)
+		logger.Warn("查找 Pom profiles 时出错", zap.String("dir", projectDir), zap.Error(err))
 	} else {
-		// 打印找到的 profiles 数量
-		log.Printf("找到 %d 个 profiles\n", len(profiles))
+		// 打印激活的 profiles
+		logger.Info("评估 profiles 的激活条件完成", zap.Strings("profiles", profiles), zap.Duration("duration", time.Since(start)))
 	}
+	return profiles
+}
 
+// ScanDepsByPluginCommandWithProfiles 使用显式指定的 profile 集合执行 Maven 插件命令扫描依赖关系。
+// profiles 为空时不传递 -P 参数，由 Maven 自行评估激活条件。
+// 与 ScanDepsByPluginCommand 一样无法取消，需要取消时使用设置了 Profiles 的 DepgraphScanner.Scan。
+func ScanDepsByPluginCommandWithProfiles(projectDir string, mvnCmdInfo *MvnCommandInfo, profiles []string) (*DepsMap, error) {
 	// 初始化 PluginGraphCmd 结构体
-	c := PluginGraphCmd{
+	return scanDepsByPluginGraphCmd(context.Background(), PluginGraphCmd{
 		MavenCmdInfo: mvnCmdInfo,
 		Profiles:     profiles,
-		Timeout:      time.Duration(120) * time.Second, // 120 s
+		Timeout:      DefaultMvnTimeout,
 		ScanDir:      projectDir,
+	})
+}
+
+// scanDepsByPluginGraphCmd 执行配置好的 Maven 图命令并收集结果文件。
+// c.OutputDir 为空时图文件写入本次扫描专用的临时目录，避免读到项目中残留或提交到仓库的图文件，扫描结束后删除该目录
+func scanDepsByPluginGraphCmd(ctx context.Context, c PluginGraphCmd) (*DepsMap, error) {
+	logger := loggerOrNop(c.Logger)
+	if c.OutputDir == "" {
+		outputDir, err := os.MkdirTemp("", "depgraph-")
+		if err != nil {
+			return nil, fmt.Errorf("创建图文件输出目录失败: %w", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(outputDir); err != nil {
+				logger.Warn("删除图文件输出目录失败", zap.String("dir", outputDir), zap.Error(err))
+			}
+		}()
+		c.OutputDir = outputDir
 	}
 
 	// 执行 Maven 图命令
-	if err := c.RunC(); err != nil {
-		// 打印执行失败的错误信息
-		log.Printf("执行 Maven 图命令失败: %v\n", err)
+	if err := c.RunContext(ctx); err != nil {
+		var buildErr *MvnBuildError
+		if c.AllowPartial && errors.As(err, &buildErr) {
+			logger.Warn("部分模块构建失败，继续收集其余模块的图文件", zap.Error(err))
+			deps, err := collectPluginOutputDir(ctx, logger, c, c.OutputDir)
+			return partialBuildResult(buildErr, deps, err)
+		}
 		return nil, err
 	}
 
-	// 打印命令执行成功的信息
-	log.Println("Maven 图命令执行成功，正在收集图文件...")
-
 	// 收集插件结果文件
-	return collectPluginResultFile(projectDir)
+	return collectPluginOutputDir(ctx, logger, c, c.OutputDir)
+}
+
+// partialBuildResult 合并 --fail-at-end 构建失败后收集到的结果：返回成功模块的依赖关系，
+// 以及包含构建失败模块与无法解析的结果文件的 *PartialResultError。没有收集到任何模块时返回构建错误
+func partialBuildResult(buildErr *MvnBuildError, deps *DepsMap, err error) (*DepsMap, error) {
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	if deps == nil || deps.Size() == 0 {
+		return nil, buildErr
+	}
+	errs := buildErr.moduleErrors()
+	if partial != nil {
+		errs = append(errs, partial.Modules...)
+	}
+	return deps, partialResult(errs)
 }
 
-// collectPluginResultFile 收集项目目录中的 dependency-graph.json 文件并解析依赖关系。
-func collectPluginResultFile(projectDir string) (*DepsMap, error) {
-	var graphPaths []string
+// ProfileScanResult 表示使用某个 profile 组合扫描得到的结果
+type ProfileScanResult struct {
+	Profiles []string // 本次扫描显式激活的 profile
+	Deps     *DepsMap // 扫描得到的依赖关系，扫描失败时为 nil
+	Err      error    // 扫描过程中的错误
+}
 
-	// 遍历项目目录，查找所有的 dependency-graph.json 文件
+// ScanDepsByProfileCombinations 依次使用每个 profile 组合单独扫描依赖，
+// 便于对互斥的 profile（如 jdk8 与 jdk17）分别得到正确的依赖图，再通过 DiffDepsMap 比较差异。
+// 单个组合扫描失败不会中断其余组合，错误记录在对应结果的 Err 中。各组合的扫描无法取消。
+func ScanDepsByProfileCombinations(projectDir string, mvnCmdInfo *MvnCommandInfo, combinations [][]string) []ProfileScanResult {
+	rs := make([]ProfileScanResult, 0, len(combinations))
+	for _, profiles := range combinations {
+		deps, err := ScanDepsByPluginCommandWithProfiles(projectDir, mvnCmdInfo, profiles)
+		rs = append(rs, ProfileScanResult{Profiles: profiles, Deps: deps, Err: err})
+	}
+	return rs
+}
+
+// graphFileName 是 depgraph-maven-plugin 以 JSON 格式输出的图文件名
+const graphFileName = "dependency-graph.json"
+
+// findResultFiles 遍历项目目录，查找所有名为 name 的结果文件，跳过 node_modules 与隐藏目录。
+// ctx 取消时停止遍历并返回 ctx 的错误
+func findResultFiles(ctx context.Context, logger *zap.Logger, projectDir, name string) ([]string, error) {
+	var paths []string
 	err := filepath.Walk(projectDir, func(path string, info fs.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil || info == nil {
 			return err
 		}
-		if info.Name() == "dependency-graph.json" {
-			// 记录找到的图文件路径
-			log.Printf("找到图文件: %s\n", path)
-			graphPaths = append(graphPaths, path)
+		if info.IsDir() && path != projectDir && (info.Name() == "node_modules" || strings.HasPrefix(info.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		if info.Name() == name {
+			// 记录找到的结果文件路径
+			logger.Debug("找到结果文件", zap.String("path", path))
+			paths = append(paths, path)
 		}
 		return nil
 	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 	if err != nil {
 		// 打印遍历目录时的错误信息
-		log.Printf("收集图文件时出错: %v\n", err)
+		logger.Warn("收集结果文件时出错", zap.String("dir", projectDir), zap.Error(err))
+	}
+	return paths, nil
+}
+
+// resultFileModulePath 返回 target 目录下的结果文件所属模块的 pom.xml 相对于项目根目录的路径
+func resultFileModulePath(logger *zap.Logger, projectDir, resultPath string) string {
+	relPath, err := filepath.Rel(projectDir, filepath.Dir(filepath.Dir(resultPath)))
+	if err != nil {
+		// 打印计算相对路径时的警告信息
+		logger.Warn("计算相对路径时出错", zap.String("path", resultPath), zap.Error(err))
+	}
+	return filepath.Join(relPath, "pom.xml")
+}
+
+// collectPluginResultFile 收集项目目录中各模块 target 目录下的 dependency-graph.json 文件并解析依赖关系。
+// 无法解析的图文件不影响其余模块，返回的 *PartialResultError 中记录这些模块的 *GraphParseError
+func collectPluginResultFile(ctx context.Context, logger *zap.Logger, progress ProgressFunc, projectDir string) (*DepsMap, error) {
+	// 遍历项目目录，查找所有的 dependency-graph.json 文件
+	graphPaths, err := findResultFiles(ctx, logger, projectDir, graphFileName)
+	if err != nil {
+		return nil, err
 	}
+	return parseGraphFiles(ctx, logger, progress, graphPaths, func(graphPath string) string {
+		return resultFileModulePath(logger, projectDir, graphPath)
+	})
+}
 
+// collectPluginOutputDir 收集 c 的 Maven 命令写入 outputDir 中的图文件并解析依赖关系。
+// 图文件所在的 <groupId>/<artifactId>/<version> 目录由 Maven 按各模块求值，据此对应到 reactor 中的模块，
+// 模块路径为该模块 pom.xml 相对于项目根目录的路径。POM 解析器得到的坐标与 Maven 不一致而无法对应时，
+// 仍然保留该图文件，记录警告并使用项目根目录的 pom.xml 作为模块路径
+func collectPluginOutputDir(ctx context.Context, logger *zap.Logger, c PluginGraphCmd, outputDir string) (*DepsMap, error) {
+	graphPaths, err := findResultFiles(ctx, logger, outputDir, graphFileName)
+	if err != nil {
+		return nil, err
+	}
+	var settingsPath string
+	if c.MavenCmdInfo != nil {
+		settingsPath = c.MavenCmdInfo.UserSettingsPath
+	}
+	r := newScanPomResolver(settingsPath, c.Profiles)
+	r.UserProperties = mavenUserProperties(logger, c.ScanDir, c.ExtraArgs)
+	modules := reactorModules(logger, r, c.ScanDir)
+	return parseGraphFiles(ctx, logger, c.Progress, graphPaths, func(graphPath string) string {
+		groupId, artifactId, version := outputDirCoordinate(outputDir, graphPath)
+		if module, ok := modules.lookup(groupId, artifactId, version); ok {
+			return module
+		}
+		logger.Warn("无法确定图文件所属的模块，使用项目根目录的 pom.xml",
+			zap.String("path", graphPath), zap.String("module", groupId+":"+artifactId+":"+version))
+		return "pom.xml"
+	})
+}
+
+// outputDirCoordinate 根据图文件在 OutputDir 中的 <groupId>/<artifactId>/<version> 目录返回模块的坐标
+func outputDirCoordinate(outputDir, graphPath string) (groupId, artifactId, version string) {
+	rel, err := filepath.Rel(outputDir, filepath.Dir(graphPath))
+	if err != nil {
+		return "", "", ""
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) != 3 {
+		return "", "", ""
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+// mavenUserProperties 返回 Maven 命令中 -D 参数定义的用户属性，
+// 参数依次取自项目 .mvn/maven.config 与 extraArgs，后定义的属性覆盖先定义的属性
+func mavenUserProperties(logger *zap.Logger, projectDir string, extraArgs []string) map[string]string {
+	var args []string
+	configPath := filepath.Join(projectDir, ".mvn", "maven.config")
+	if data, err := os.ReadFile(configPath); err == nil {
+		args = strings.Fields(string(data))
+	} else if !os.IsNotExist(err) {
+		logger.Warn("读取 maven.config 时出错", zap.String("path", configPath), zap.Error(err))
+	}
+	args = append(args, extraArgs...)
+
+	properties := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		var define string
+		switch arg := args[i]; {
+		case (arg == "-D" || arg == "--define") && i+1 < len(args):
+			i++
+			define = args[i]
+		case strings.HasPrefix(arg, "--define="):
+			define = strings.TrimPrefix(arg, "--define=")
+		case strings.HasPrefix(arg, "-D") && len(arg) > 2:
+			define = strings.TrimPrefix(arg, "-D")
+		default:
+			continue
+		}
+		// 与 Maven 一致，没有值的 -Dkey 相当于 -Dkey=true
+		key, value, ok := strings.Cut(define, "=")
+		if !ok {
+			value = "true"
+		}
+		properties[key] = value
+	}
+	return properties
+}
+
+// reactorModuleIndex 按坐标查找 reactor 中模块的 pom.xml 相对于项目根目录的路径
+type reactorModuleIndex struct {
+	byCoordinate map[string]string   // groupId:artifactId:version 到模块路径
+	byName       map[string][]string // groupId:artifactId 到模块路径，version 不同的模块可能有多个
+}
+
+// lookup 返回坐标对应的模块路径。version 对应不上（如 POM 解析器无法求值 ${revision}）时，
+// groupId:artifactId 只对应一个模块的也视为找到
+func (m reactorModuleIndex) lookup(groupId, artifactId, version string) (string, bool) {
+	if module, ok := m.byCoordinate[groupId+":"+artifactId+":"+version]; ok {
+		return module, true
+	}
+	if modules := m.byName[groupId+":"+artifactId]; len(modules) == 1 {
+		return modules[0], true
+	}
+	return "", false
+}
+
+// reactorModules 返回 reactor 中各模块的坐标索引。坐标取自 r 生成的有效 POM，
+// 与 Maven 一样包含从父 POM 继承及插值后的 groupId 与 version；解析失败时返回空的索引
+func reactorModules(logger *zap.Logger, r *PomResolver, projectDir string) reactorModuleIndex {
+	rs := reactorModuleIndex{byCoordinate: map[string]string{}, byName: map[string][]string{}}
+	r.Logger = logger
+	if r.LocalRepository != nil {
+		r.LocalRepository.Logger = logger
+	}
+	poms, moduleErrs, err := r.resolveReactor(projectDir)
+	if err != nil {
+		logger.Warn("解析项目模块时出错", zap.String("dir", projectDir), zap.Error(err))
+		return rs
+	}
+	for _, e := range moduleErrs {
+		logger.Warn("解析模块时出错", zap.String("module", e.Module), zap.Error(e.Err))
+	}
+
+	for _, pom := range poms {
+		c := pom.Coordinate
+		module := relativeModulePath(poms[0].Path, pom.Path)
+		rs.byCoordinate[c.GroupId+":"+c.ArtifactId+":"+c.Version] = module
+		rs.byName[c.GroupId+":"+c.ArtifactId] = append(rs.byName[c.GroupId+":"+c.ArtifactId], module)
+	}
+	return rs
+}
+
+// parseGraphFiles 解析图文件，modulePath 返回图文件所属模块的路径，每个文件解析完成后发出 graph-file-parsed 事件。
+// 无法解析的图文件不影响其余模块，返回的 *PartialResultError 中记录这些模块的 *GraphParseError
+func parseGraphFiles(ctx context.Context, logger *zap.Logger, progress ProgressFunc, graphPaths []string, modulePath func(graphPath string) string) (*DepsMap, error) {
 	// 初始化 DepsMap 以存储依赖关系
 	rs := newDepsMap()
+	var errs []ModuleError
 
 	// 遍历所有找到的图文件，解析并存储依赖关系
-	for _, graphPath := range graphPaths {
-		log.Printf("正在处理图文件: %s\n", graphPath)
-		var g PluginGraphOutput
+	for i, graphPath := range graphPaths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		module := modulePath(graphPath)
+		fileLogger := logger.With(zap.String("module", module), zap.String("path", graphPath))
+		g := PluginGraphOutput{Logger: fileLogger}
+		parsed := func(err error) {
+			progress.emit(ProgressEvent{Phase: ProgressGraphFileParsed, Duration: time.Since(start), Module: module, Index: i + 1, Total: len(graphPaths), Path: graphPath, Err: err})
+		}
 
 		// 从文件中读取图数据
 		if err := g.ReadFromFile(graphPath); err != nil {
 			// 打印读取文件时的错误信息，并继续处理下一个文件
-			log.Printf("读取图文件时出错: %v\n", err)
+			fileLogger.Warn("读取图文件时出错", zap.Error(err))
+			errs = append(errs, ModuleError{Module: module, Err: &GraphParseError{Path: graphPath, Err: err}})
+			parsed(err)
 			continue
 		}
 
@@ -84,41 +329,17 @@ func collectPluginResultFile(projectDir string) (*DepsMap, error) {
 		tree, err := g.Tree()
 		if err != nil {
 			// 打印构建依赖树时的错误信息，并继续处理下一个文件
-			log.Printf("构建依赖树时出错: %v\n", err)
+			fileLogger.Warn("构建依赖树时出错", zap.Error(err))
+			errs = append(errs, ModuleError{Module: module, Err: &GraphParseError{Path: graphPath, Err: err}})
+			parsed(err)
 			continue
 		}
 
-		// 计算图文件所在目录相对于项目根目录的相对路径
-		relPath, err := filepath.Rel(projectDir, filepath.Dir(filepath.Dir(graphPath)))
-		if err != nil {
-			// 打印计算相对路径时的警告信息
-			log.Printf("计算相对路径时出错: %v\n", err)
-		}
-
-		// 将解析后的依赖关系存储到 DepsMap 中
-		rs.put(tree.Coordinate, tree.Children, filepath.Join(relPath, "pom.xml"))
-	}
-
-	return rs, nil
-}
-
-// findPomProfiles 解析指定的 pom.xml 文件，查找所有的 profiles。
-func findPomProfiles(pomPath string) ([]string, error) {
-	// 解析 pom.xml 文件
-	project, err := gopom.Parse(pomPath)
-	if err != nil {
-		return nil, err
-	}
-
-	// 初始化 profiles 切片
-	var profiles []string
-
-	// 如果项目中存在 profiles，提取每个 profile 的 ID
-	if project.Profiles != nil {
-		for _, profile := range *project.Profiles {
-			profiles = append(profiles, *profile.ID)
-		}
+		// 将解析后的依赖关系存储到 DepsMap 中，路径为图文件所属模块的路径
+		rs.put(tree.Coordinate, tree.Children, module)
+		fileLogger.Debug("解析图文件完成", zap.Duration("duration", time.Since(start)))
+		parsed(nil)
 	}
 
-	return profiles, nil
+	return rs, partialResult(errs)
 }
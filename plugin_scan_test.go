@@ -0,0 +1,45 @@
+package pom_component_parsing
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMavenUserProperties(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		extraArgs []string
+		want      map[string]string
+	}{
+		{
+			name: "没有 -D 参数",
+			want: map[string]string{},
+		},
+		{
+			name:      "额外参数中的各种写法",
+			extraArgs: []string{"-U", "-Drevision=1.2.0", "-D", "changelist=-SNAPSHOT", "--define=sha1=abc", "-DskipTests", "-P", "prod"},
+			want:      map[string]string{"revision": "1.2.0", "changelist": "-SNAPSHOT", "sha1": "abc", "skipTests": "true"},
+		},
+		{
+			name:      "额外参数覆盖 maven.config",
+			config:    "-Drevision=1.0.0\n-Dchangelist=\n--batch-mode\n",
+			extraArgs: []string{"-Drevision=2.0.0"},
+			want:      map[string]string{"revision": "2.0.0", "changelist": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.config != "" {
+				writeTestFile(t, dir, ".mvn/maven.config", tt.config)
+			}
+			if got := mavenUserProperties(zaptest.NewLogger(t), dir, tt.extraArgs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mavenUserProperties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package pom_component_parsing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Interpolator POM 属性插值器
+// 按照 Maven 的规则解析 ${...} 引用，支持属性之间的递归引用并检测循环引用
+type Interpolator struct {
+	Model            map[string]string // project.* 模型引用，如 project.version、project.parent.groupId
+	Properties       map[string]string // 当前 POM 及父 POM 中 <properties> 声明的属性
+	UserProperties   map[string]string // 命令行 -D 传入的用户属性，优先级最高，常用于 ${revision} 等 CI 友好版本
+	SystemProperties map[string]string // 系统属性，如 user.home、os.name
+	Env              map[string]string // 环境变量，通过 ${env.XXX} 引用
+}
+
+// ErrPropertyCycle 表示属性之间存在循环引用
+var ErrPropertyCycle = errors.New("属性存在循环引用")
+
+// ciFriendlyProperties 是 Maven CI 友好版本使用的属性，未定义时按空字符串处理
+var ciFriendlyProperties = map[string]struct{}{
+	"sha1":       {},
+	"changelist": {},
+}
+
+// maxInterpolatePasses 限制单个字符串的插值轮数，用于处理 ${a.${b}} 这类嵌套引用
+const maxInterpolatePasses = 32
+
+// propertyRegexp 匹配最内层的 ${...} 属性引用
+var propertyRegexp = regexp.MustCompile(`\$\{([^${}]+)\}`)
+
+// NewInterpolator 使用给定的属性创建插值器，系统属性与环境变量取自当前进程
+func NewInterpolator(properties map[string]string) *Interpolator {
+	return &Interpolator{
+		Model:            map[string]string{},
+		Properties:       properties,
+		UserProperties:   map[string]string{},
+		SystemProperties: defaultSystemProperties(),
+		Env:              environMap(),
+	}
+}
+
+// Lookup 查找属性的原始值（未插值）
+// 查找顺序：env.* 环境变量，project.* 模型引用，用户属性，POM 属性，系统属性
+func (i *Interpolator) Lookup(key string) (string, bool) {
+	if strings.HasPrefix(key, "env.") {
+		v, ok := i.Env[strings.TrimPrefix(key, "env.")]
+		return v, ok
+	}
+
+	// pom.* 是 project.* 的旧写法
+	modelKey := key
+	if strings.HasPrefix(modelKey, "pom.") {
+		modelKey = "project." + strings.TrimPrefix(modelKey, "pom.")
+	}
+	if v, ok := i.Model[modelKey]; ok {
+		return v, true
+	}
+
+	for _, m := range []map[string]string{i.UserProperties, i.Properties, i.SystemProperties} {
+		if v, ok := m[key]; ok {
+			return v, true
+		}
+	}
+
+	if _, ok := ciFriendlyProperties[key]; ok {
+		return "", true
+	}
+	return "", false
+}
+
+// Interpolate 替换字符串中的所有 ${...} 引用
+// 无法解析的引用保持原样；检测到循环引用时返回 ErrPropertyCycle，
+// 同时返回尽可能完成插值的结果
+func (i *Interpolator) Interpolate(s string) (string, error) {
+	return i.interpolate(s, nil)
+}
+
+// interpolate 在给定的引用栈下进行插值，stack 记录正在解析的属性名用于检测循环
+func (i *Interpolator) interpolate(s string, stack []string) (string, error) {
+	var firstErr error
+	for pass := 0; pass < maxInterpolatePasses && strings.Contains(s, "${"); pass++ {
+		replaced := propertyRegexp.ReplaceAllStringFunc(s, func(m string) string {
+			v, err := i.resolve(m[2:len(m)-1], stack)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return m
+			}
+			return v
+		})
+		if replaced == s {
+			break
+		}
+		s = replaced
+	}
+	return s, firstErr
+}
+
+// resolve 解析单个属性并递归插值其值
+func (i *Interpolator) resolve(key string, stack []string) (string, error) {
+	for idx, k := range stack {
+		if k == key {
+			chain := append(append([]string{}, stack[idx:]...), key)
+			return "", fmt.Errorf("%w: %s", ErrPropertyCycle, strings.Join(chain, " -> "))
+		}
+	}
+
+	raw, ok := i.Lookup(key)
+	if !ok {
+		return "${" + key + "}", nil
+	}
+	return i.interpolate(raw, append(stack[:len(stack):len(stack)], key))
+}
+
+// Interpolate 使用插值器解析坐标中的 ${...} 引用，使从原始 POM 读取的坐标能够满足 Complete
+func (c Coordinate) Interpolate(i *Interpolator) (Coordinate, error) {
+	var errs []error
+	field := func(s string) string {
+		v, err := i.Interpolate(s)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return v
+	}
+
+	rs := c
+	rs.GroupId = field(c.GroupId)
+	rs.ArtifactId = field(c.ArtifactId)
+	rs.Version = field(c.Version)
+	rs.Type = field(c.Type)
+	rs.Classifier = field(c.Classifier)
+	return rs, errors.Join(errs...)
+}
+
+// defaultSystemProperties 返回与 Java 系统属性对应的常用属性
+func defaultSystemProperties() map[string]string {
+	props := map[string]string{
+		"os.name":        javaOsName(),
+		"os.arch":        javaOsArch(),
+		"file.separator": string(filepath.Separator),
+		"path.separator": string(filepath.ListSeparator),
+		"line.separator": "\n",
+	}
+	if runtime.GOOS == "windows" {
+		props["line.separator"] = "\r\n"
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		props["user.home"] = home
+	}
+	if wd, err := os.Getwd(); err == nil {
+		props["user.dir"] = wd
+	}
+	if javaHome := os.Getenv("JAVA_HOME"); javaHome != "" {
+		props["java.home"] = javaHome
+	}
+	return props
+}
+
+// javaOsName 返回与 Java os.name 系统属性风格一致的操作系统名称
+func javaOsName() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "Linux"
+	case "darwin":
+		return "Mac OS X"
+	case "windows":
+		return "Windows"
+	case "freebsd":
+		return "FreeBSD"
+	default:
+		return runtime.GOOS
+	}
+}
+
+// environMap 将当前进程的环境变量转换为 map
+func environMap() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
@@ -0,0 +1,98 @@
+package pom_component_parsing
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestInterpolator_Interpolate(t *testing.T) {
+	i := NewInterpolator(map[string]string{
+		"spring.version":               "5.3.30",
+		"spring.boot":                  "${spring.version}",
+		"revision":                     "1.2.0",
+		"lib.suffix":                   "core",
+		"name.core":                    "nested",
+		"project.build.sourceEncoding": "UTF-8",
+	})
+	i.Model["project.version"] = "${revision}${sha1}${changelist}"
+	i.Model["project.groupId"] = "com.example"
+	i.Env["MY_HOME"] = "/opt/home"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "普通属性", input: "${spring.version}", want: "5.3.30"},
+		{name: "递归属性", input: "${spring.boot}", want: "5.3.30"},
+		{name: "CI 友好版本", input: "${project.version}", want: "1.2.0"},
+		{name: "pom 前缀", input: "${pom.groupId}", want: "com.example"},
+		{name: "环境变量", input: "${env.MY_HOME}/lib", want: "/opt/home/lib"},
+		{name: "嵌套引用", input: "${name.${lib.suffix}}", want: "nested"},
+		{name: "project 前缀的普通属性", input: "${project.build.sourceEncoding}", want: "UTF-8"},
+		{name: "系统属性", input: "${file.separator}", want: string(filepath.Separator)},
+		{name: "未定义属性保持原样", input: "${unknown}-1", want: "${unknown}-1"},
+		{name: "无引用", input: "1.0.0", want: "1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.Interpolate(tt.input)
+			if err != nil {
+				t.Fatalf("Interpolate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Interpolate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolator_UserPropertiesOverride(t *testing.T) {
+	i := NewInterpolator(map[string]string{"revision": "1.0.0-SNAPSHOT"})
+	i.UserProperties["revision"] = "2.0.0"
+
+	got, err := i.Interpolate("${revision}")
+	if err != nil || got != "2.0.0" {
+		t.Errorf("Interpolate() = %v, %v, want 2.0.0", got, err)
+	}
+}
+
+func TestInterpolator_Cycle(t *testing.T) {
+	i := NewInterpolator(map[string]string{
+		"a": "${b}",
+		"b": "${c}",
+		"c": "${a}",
+	})
+
+	got, err := i.Interpolate("v-${a}")
+	if !errors.Is(err, ErrPropertyCycle) {
+		t.Fatalf("Interpolate() error = %v, want ErrPropertyCycle", err)
+	}
+	if got != "v-${a}" {
+		t.Errorf("Interpolate() = %v, want v-${a}", got)
+	}
+}
+
+func TestCoordinate_Interpolate(t *testing.T) {
+	i := NewInterpolator(map[string]string{"guava.version": "32.1.2-jre"})
+	i.Model["project.groupId"] = "com.example"
+
+	c := Coordinate{GroupId: "${project.groupId}", ArtifactId: "guava", Version: "${guava.version}"}
+	if c.Complete() {
+		t.Fatalf("插值前 Complete() 应为 false")
+	}
+
+	got, err := c.Interpolate(i)
+	if err != nil {
+		t.Fatalf("Interpolate() error = %v", err)
+	}
+	want := Coordinate{GroupId: "com.example", ArtifactId: "guava", Version: "32.1.2-jre"}
+	if got != want {
+		t.Errorf("Interpolate() = %v, want %v", got, want)
+	}
+	if !got.Complete() {
+		t.Errorf("插值后 Complete() 应为 true")
+	}
+}
@@ -0,0 +1,639 @@
+package pom_component_parsing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vifraa/gopom"
+	"go.uber.org/zap"
+)
+
+// PomResolver 纯 Go 实现的 POM 解析器
+// 在系统中没有可用的 mvn 命令时，通过直接解析 pom.xml 生成有效 POM（effective POM），
+// 完成父 POM 合并、${...} 属性插值以及 dependencyManagement 的版本管理
+type PomResolver struct {
+	UserProperties  map[string]string // 用户属性，相当于 mvn 的 -D 参数，如 revision
+	LocalRepository *LocalRepository  // 本地仓库，用于查找不在项目目录中的父 POM
+	Activation      ActivationContext // profile 激活条件的评估环境，激活的 profile 会合并到有效 POM 中
+	Logger          *zap.Logger       // 记录解析过程中可忽略的错误，为 nil 时不输出日志
+
+	projects map[string]*gopom.Project // 按 pom.xml 绝对路径缓存已解析的项目
+	poms     map[string]*EffectivePom  // 按 pom.xml 绝对路径缓存已生成的有效 POM
+}
+
+// EffectivePom 表示合并父 POM 并完成属性插值后的有效 POM
+type EffectivePom struct {
+	Path           string                       // pom.xml 的绝对路径
+	Coordinate     Coordinate                   // 模块自身的坐标
+	Packaging      string                       // 打包类型，默认为 jar
+	Parents        []PomSource                  // 父 POM 继承链，从直接父 POM 到最顶层父 POM
+	Properties     map[string]string            // 合并后的属性，子 POM 覆盖父 POM
+	UserProperties map[string]string            // 解析时使用的用户属性（-D），优先于 Properties
+	Model          map[string]string            // project.* 模型引用，如 project.version
+	Managed        map[string]ManagedDependency // dependencyManagement 中声明及通过 BOM 导入的依赖，键为 Coordinate.ManagementKey
+	Dependencies   []Dependency                 // 完成插值与版本管理后的直接依赖（包含从父 POM 继承的依赖）
+	Versions       map[string]VersionSource     // 每个直接依赖的版本来源，键为 Coordinate.ManagementKey
+	Modules        []string                     // 子模块 pom.xml 的绝对路径
+
+	managedDecls    []dependencyDecl // 继承链上 dependencyManagement 的原始声明，父 POM 在前
+	dependencyDecls []dependencyDecl // 继承链上 dependencies 的原始声明，父 POM 在前
+}
+
+// PomSource 表示继承链中的一个 POM
+type PomSource struct {
+	Coordinate        // POM 的坐标
+	Path       string // pom 文件路径，可能位于项目目录或本地仓库中
+}
+
+// ManagedDependency 表示 dependencyManagement 中声明的一个依赖
+type ManagedDependency struct {
+	Coordinate
+	Scope  string     // 管理的作用域，为空表示未指定
+	Source PomSource  // 声明该依赖管理的 POM
+	Bom    *PomSource // 通过 <scope>import</scope> 引入时对应的 BOM，直接声明时为 nil
+}
+
+// VersionSourceKind 表示依赖版本的来源类型
+type VersionSourceKind string
+
+const (
+	VersionFromSelf   VersionSourceKind = "self"   // 版本由 POM 自身的依赖或依赖管理声明
+	VersionFromParent VersionSourceKind = "parent" // 版本由某个父 POM 的依赖或依赖管理声明
+	VersionFromBom    VersionSourceKind = "bom"    // 版本由导入的 BOM 管理
+)
+
+// VersionSource 描述一个依赖的版本来自哪里
+type VersionSource struct {
+	Kind   VersionSourceKind // 来源类型
+	Source PomSource         // 提供版本的 POM，来源为 BOM 时为该 BOM
+}
+
+// dependencyDecl 记录 POM 中声明的原始依赖及其来源
+// 继承时保留未插值的声明，以便与 Maven 一致地在子 POM 的上下文中完成插值
+type dependencyDecl struct {
+	dependency gopom.Dependency
+	source     PomSource
+}
+
+// NewPomResolver 创建一个新的 PomResolver 实例，本地仓库取自 settings.xml 或默认的 ~/.m2/repository
+func NewPomResolver() *PomResolver {
+	return &PomResolver{
+		UserProperties:  map[string]string{},
+		LocalRepository: DefaultLocalRepository(""),
+		Activation:      NewActivationContext(""),
+		projects:        map[string]*gopom.Project{},
+		poms:            map[string]*EffectivePom{},
+	}
+}
+
+// newScanPomResolver 创建从 settingsPath 读取本地仓库位置、显式激活 profiles 的 PomResolver，
+// settingsPath 为空时使用 Maven 的默认配置
+func newScanPomResolver(settingsPath string, profiles []string) *PomResolver {
+	r := NewPomResolver()
+	r.LocalRepository = DefaultLocalRepository(settingsPath)
+	r.Activation.ExplicitProfiles = profiles
+	return r
+}
+
+// ScanDepsByPomResolver 使用纯 Go 的 POM 解析器扫描项目依赖
+// 只能得到每个模块的直接依赖，不包含传递依赖。
+// 子模块解析失败时仍返回其余模块，失败的子模块记录在返回的 *PartialResultError 中
+func ScanDepsByPomResolver(projectDir string) (*DepsMap, error) {
+	return scanDepsByPomResolver(NewPomResolver(), zap.NewNop(), nil, projectDir)
+}
+
+// scanDepsByPomResolver 与 ScanDepsByPomResolver 相同，使用 r 解析项目，解析过程中的日志输出到 logger，
+// progress 不为空时为每个模块发出 module-resolved 事件
+func scanDepsByPomResolver(r *PomResolver, logger *zap.Logger, progress ProgressFunc, projectDir string) (*DepsMap, error) {
+	r.Logger = logger
+	if r.LocalRepository != nil {
+		r.LocalRepository.Logger = logger
+	}
+	poms, moduleErrs, err := r.resolveReactor(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := newDepsMap()
+	for i, pom := range poms {
+		relPath, err := filepath.Rel(projectDir, pom.Path)
+		if err != nil {
+			logger.Warn("计算相对路径时出错", zap.String("path", pom.Path), zap.Error(err))
+			relPath = "pom.xml"
+		}
+		rs.put(pom.Coordinate, pom.Dependencies, relPath)
+		progress.emit(ProgressEvent{Phase: ProgressModuleResolved, Module: relPath, Index: i + 1, Total: len(poms)})
+	}
+	return rs, partialResult(moduleErrs)
+}
+
+// ResolveReactor 从项目根目录的 pom.xml 开始，递归解析 modules 中声明的所有模块
+// 返回按遍历顺序排列的有效 POM 列表
+func (r *PomResolver) ResolveReactor(projectDir string) ([]*EffectivePom, error) {
+	rs, _, err := r.resolveReactor(projectDir)
+	return rs, err
+}
+
+// resolveReactor 与 ResolveReactor 相同，同时返回解析失败的子模块
+func (r *PomResolver) resolveReactor(projectDir string) ([]*EffectivePom, []ModuleError, error) {
+	rootPom, err := filepath.Abs(filepath.Join(projectDir, "pom.xml"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rs []*EffectivePom
+	var moduleErrs []ModuleError
+	visited := map[string]bool{}
+
+	var walk func(pomPath string) error
+	walk = func(pomPath string) error {
+		if visited[pomPath] {
+			return nil
+		}
+		visited[pomPath] = true
+
+		pom, err := r.Resolve(pomPath)
+		if err != nil {
+			return err
+		}
+		rs = append(rs, pom)
+
+		for _, module := range pom.Modules {
+			if err := walk(module); err != nil {
+				// 单个子模块解析失败不影响其余模块
+				r.logger().Warn("解析子模块时出错", zap.String("module", module), zap.Error(err))
+				moduleErrs = append(moduleErrs, ModuleError{Module: relativeModulePath(rootPom, module), Err: err})
+			}
+		}
+		return nil
+	}
+
+	if err := walk(rootPom); err != nil {
+		return nil, nil, err
+	}
+	return rs, moduleErrs, nil
+}
+
+// relativeModulePath 返回子模块 pom.xml 相对于根 pom.xml 所在目录的路径，无法计算时返回原路径
+func relativeModulePath(rootPom, modulePom string) string {
+	if rel, err := filepath.Rel(filepath.Dir(rootPom), modulePom); err == nil {
+		return rel
+	}
+	return modulePom
+}
+
+// Resolve 解析指定的 pom.xml 并生成有效 POM
+func (r *PomResolver) Resolve(pomPath string) (*EffectivePom, error) {
+	return r.resolve(pomPath, map[string]bool{})
+}
+
+// resolve 生成有效 POM，resolving 用于检测父 POM 之间的循环引用
+func (r *PomResolver) resolve(pomPath string, resolving map[string]bool) (*EffectivePom, error) {
+	pomPath, err := filepath.Abs(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	if pom, ok := r.poms[pomPath]; ok {
+		return pom, nil
+	}
+	if resolving[pomPath] {
+		return nil, fmt.Errorf("检测到父 POM 循环引用: %s", pomPath)
+	}
+	resolving[pomPath] = true
+	logger := r.logger()
+
+	project, err := r.parse(pomPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pom := &EffectivePom{
+		Path:           pomPath,
+		Packaging:      "jar",
+		Properties:     map[string]string{},
+		UserProperties: r.UserProperties,
+		Model:          map[string]string{},
+		Managed:        map[string]ManagedDependency{},
+		Versions:       map[string]VersionSource{},
+	}
+	interpolator := r.interpolator(pom)
+
+	// 先继承父 POM 的坐标、属性、依赖与依赖管理
+	if project.Parent != nil {
+		if parent := r.resolveParent(pomPath, project, resolving); parent != nil {
+			pom.Coordinate.GroupId = parent.Coordinate.GroupId
+			pom.Coordinate.Version = parent.Coordinate.Version
+			pom.Parents = append([]PomSource{parent.Source()}, parent.Parents...)
+			for k, v := range parent.Properties {
+				pom.Properties[k] = v
+			}
+			pom.managedDecls = append(pom.managedDecls, parent.managedDecls...)
+			pom.dependencyDecls = append(pom.dependencyDecls, parent.dependencyDecls...)
+		}
+		// 显式声明的 parent 坐标优先于解析得到的父 POM
+		if v := deref(project.Parent.GroupID); v != "" {
+			pom.Coordinate.GroupId = v
+		}
+		if v := deref(project.Parent.Version); v != "" {
+			pom.Coordinate.Version = v
+		}
+		pom.Model["project.parent.groupId"] = deref(project.Parent.GroupID)
+		pom.Model["project.parent.artifactId"] = deref(project.Parent.ArtifactID)
+		pom.Model["project.parent.version"] = deref(project.Parent.Version)
+	}
+
+	// 再用当前 POM 自身的声明覆盖继承值
+	if v := deref(project.GroupID); v != "" {
+		pom.Coordinate.GroupId = v
+	}
+	pom.Coordinate.ArtifactId = deref(project.ArtifactID)
+	if v := deref(project.Version); v != "" {
+		pom.Coordinate.Version = v
+	}
+	if v := deref(project.Packaging); v != "" {
+		pom.Packaging = v
+	}
+	if project.Properties != nil {
+		for k, v := range project.Properties.Entries {
+			pom.Properties[k] = v
+		}
+	}
+
+	// 激活的 profile 中的属性覆盖 POM 自身声明的属性
+	profiles := r.activation().ActiveProfiles(project, filepath.Dir(pomPath))
+	for _, profile := range profiles {
+		if profile.Properties != nil {
+			for k, v := range profile.Properties.Entries {
+				pom.Properties[k] = v
+			}
+		}
+	}
+
+	pom.Model["project.groupId"] = pom.Coordinate.GroupId
+	pom.Model["project.artifactId"] = pom.Coordinate.ArtifactId
+	pom.Model["project.version"] = pom.Coordinate.Version
+	pom.Model["project.packaging"] = pom.Packaging
+	pom.Model["project.basedir"] = filepath.Dir(pomPath)
+	pom.Model["project.build.directory"] = filepath.Join(filepath.Dir(pomPath), "target")
+	pom.Model["basedir"] = filepath.Dir(pomPath)
+
+	pom.Coordinate = interpolateCoordinate(logger, interpolator, pom.Coordinate)
+	// 坐标插值完成后更新模型引用，避免后续重复解析
+	pom.Model["project.groupId"] = pom.Coordinate.GroupId
+	pom.Model["project.version"] = pom.Coordinate.Version
+
+	// 追加当前 POM 自身的依赖声明
+	self := pom.Source()
+	if project.DependencyManagement != nil && project.DependencyManagement.Dependencies != nil {
+		for _, dep := range *project.DependencyManagement.Dependencies {
+			pom.managedDecls = append(pom.managedDecls, dependencyDecl{dependency: dep, source: self})
+		}
+	}
+	if project.Dependencies != nil {
+		for _, dep := range *project.Dependencies {
+			pom.dependencyDecls = append(pom.dependencyDecls, dependencyDecl{dependency: dep, source: self})
+		}
+	}
+	for _, profile := range profiles {
+		if profile.DependencyManagement != nil && profile.DependencyManagement.Dependencies != nil {
+			for _, dep := range *profile.DependencyManagement.Dependencies {
+				pom.managedDecls = append(pom.managedDecls, dependencyDecl{dependency: dep, source: self})
+			}
+		}
+		if profile.Dependencies != nil {
+			for _, dep := range *profile.Dependencies {
+				pom.dependencyDecls = append(pom.dependencyDecls, dependencyDecl{dependency: dep, source: self})
+			}
+		}
+	}
+
+	// 在当前 POM 的上下文中插值所有继承的依赖管理，子 POM 的声明覆盖父 POM
+	var imports []ManagedDependency
+	for _, decl := range pom.managedDecls {
+		m := ManagedDependency{
+			Coordinate: interpolateCoordinate(logger, interpolator, Coordinate{
+				GroupId:    deref(decl.dependency.GroupID),
+				ArtifactId: deref(decl.dependency.ArtifactID),
+				Version:    deref(decl.dependency.Version),
+				Type:       deref(decl.dependency.Type),
+				Classifier: deref(decl.dependency.Classifier),
+			}),
+			Scope:  interpolate(logger, interpolator, deref(decl.dependency.Scope)),
+			Source: decl.source,
+		}
+		if m.Scope == "import" {
+			imports = append(imports, m)
+			continue
+		}
+		pom.Managed[m.ManagementKey()] = m
+	}
+	r.importBoms(pom, imports, resolving)
+
+	// 生成直接依赖，缺失的版本与作用域由 dependencyManagement 补全，子 POM 的声明覆盖父 POM
+	index := map[string]int{}
+	for _, decl := range pom.dependencyDecls {
+		d, source := pom.managedDependency(logger, interpolator, decl)
+		if d.IsVersionRange() && r.LocalRepository != nil {
+			// 版本范围使用本地仓库元数据中满足范围的最高版本，无法确定时保留原始范围
+			if v, err := r.LocalRepository.ResolveVersion(d.GroupId, d.ArtifactId, d.Version); err != nil {
+				logger.Warn("解析版本范围时出错", zap.Stringer("coordinate", d.Coordinate), zap.Error(err))
+			} else {
+				d.Version = v
+			}
+		}
+		if source.Kind != "" {
+			pom.Versions[d.ManagementKey()] = source
+		}
+		if idx, ok := index[d.ManagementKey()]; ok {
+			pom.Dependencies[idx] = d
+			continue
+		}
+		index[d.ManagementKey()] = len(pom.Dependencies)
+		pom.Dependencies = append(pom.Dependencies, d)
+	}
+
+	// 记录子模块路径（包括激活的 profile 中声明的模块），module 既可以是目录也可以是 pom 文件
+	var modules []string
+	if project.Modules != nil {
+		modules = append(modules, *project.Modules...)
+	}
+	for _, profile := range profiles {
+		if profile.Modules != nil {
+			modules = append(modules, *profile.Modules...)
+		}
+	}
+	for _, module := range modules {
+		module = strings.TrimSpace(interpolate(logger, interpolator, module))
+		if module == "" {
+			continue
+		}
+		modulePath := filepath.Join(filepath.Dir(pomPath), module)
+		if !strings.HasSuffix(modulePath, ".xml") {
+			modulePath = filepath.Join(modulePath, "pom.xml")
+		}
+		pom.Modules = append(pom.Modules, modulePath)
+	}
+
+	if r.poms == nil {
+		r.poms = map[string]*EffectivePom{}
+	}
+	r.poms[pomPath] = pom
+	return pom, nil
+}
+
+// activation 返回合并了用户属性的激活上下文，-D 属性同样可以激活 profile
+func (r *PomResolver) activation() ActivationContext {
+	a := r.Activation
+	a.Properties = map[string]string{}
+	for k, v := range r.Activation.Properties {
+		a.Properties[k] = v
+	}
+	for k, v := range r.UserProperties {
+		a.Properties[k] = v
+	}
+	return a
+}
+
+// Source 返回有效 POM 自身作为继承链节点的信息
+func (p *EffectivePom) Source() PomSource {
+	return PomSource{Coordinate: p.Coordinate, Path: p.Path}
+}
+
+// Chain 返回完整的继承链，第一个元素为 POM 自身，其后依次为各级父 POM
+func (p *EffectivePom) Chain() []PomSource {
+	return append([]PomSource{p.Source()}, p.Parents...)
+}
+
+// resolveParent 查找并解析父 POM
+// 依次尝试 relativePath（默认 ../pom.xml）与本地仓库，都找不到时返回 nil，此时只继承 parent 元素中声明的坐标
+func (r *PomResolver) resolveParent(pomPath string, project *gopom.Project, resolving map[string]bool) *EffectivePom {
+	// parent 元素中的版本可能引用当前 POM 的属性或 -D 用户属性，如 ${revision}
+	var rawProperties map[string]string
+	if project.Properties != nil {
+		rawProperties = project.Properties.Entries
+	}
+	bootstrap := NewInterpolator(rawProperties)
+	bootstrap.UserProperties = r.UserProperties
+
+	declared := interpolateCoordinate(r.logger(), bootstrap, Coordinate{
+		GroupId:    deref(project.Parent.GroupID),
+		ArtifactId: deref(project.Parent.ArtifactID),
+		Version:    deref(project.Parent.Version),
+	})
+
+	// 本地仓库中的 POM 不使用 relativePath
+	inRepository := r.LocalRepository != nil && r.LocalRepository.Contains(pomPath)
+	if !inRepository {
+		if parent := r.resolveRelativeParent(pomPath, project.Parent, declared, resolving); parent != nil {
+			return parent
+		}
+	}
+
+	if r.LocalRepository != nil {
+		if parentPath, ok := r.LocalRepository.FindPom(declared); ok {
+			parent, err := r.resolve(parentPath, resolving)
+			if err == nil {
+				return parent
+			}
+			r.logger().Warn("解析父 POM 时出错", zap.String("path", parentPath), zap.Error(err))
+		}
+	}
+
+	r.logger().Warn("未找到父 POM", zap.String("path", pomPath), zap.Stringer("parent", declared))
+	return nil
+}
+
+// resolveRelativeParent 根据 relativePath 在项目目录中查找父 POM，坐标不匹配时返回 nil
+func (r *PomResolver) resolveRelativeParent(pomPath string, parent *gopom.Parent, declared Coordinate, resolving map[string]bool) *EffectivePom {
+	relativePath := "../pom.xml"
+	if parent.RelativePath != nil {
+		relativePath = strings.TrimSpace(*parent.RelativePath)
+	}
+	// 空的 <relativePath/> 表示不从本地目录查找父 POM
+	if relativePath == "" {
+		return nil
+	}
+
+	parentPath := filepath.Join(filepath.Dir(pomPath), relativePath)
+	if info, err := os.Stat(parentPath); err == nil && info.IsDir() {
+		parentPath = filepath.Join(parentPath, "pom.xml")
+	}
+	if _, err := os.Stat(parentPath); err != nil {
+		return nil
+	}
+
+	pom, err := r.resolve(parentPath, resolving)
+	if err != nil {
+		r.logger().Warn("解析父 POM 时出错", zap.String("path", parentPath), zap.Error(err))
+		return nil
+	}
+
+	// 与 Maven 一致，relativePath 指向的 POM 坐标不匹配时忽略
+	if pom.Coordinate.GroupId != declared.GroupId || pom.Coordinate.ArtifactId != declared.ArtifactId {
+		return nil
+	}
+	if !strings.Contains(declared.Version, "${") && pom.Coordinate.Version != declared.Version {
+		return nil
+	}
+	return pom
+}
+
+// parse 解析 pom.xml 文件并缓存结果
+func (r *PomResolver) parse(pomPath string) (*gopom.Project, error) {
+	if project, ok := r.projects[pomPath]; ok {
+		return project, nil
+	}
+	project, err := gopom.Parse(pomPath)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", pomPath, err)
+	}
+	if r.projects == nil {
+		r.projects = map[string]*gopom.Project{}
+	}
+	r.projects[pomPath] = project
+	return project, nil
+}
+
+// interpolator 创建使用有效 POM 属性、模型引用与用户属性的插值器
+func (r *PomResolver) interpolator(pom *EffectivePom) *Interpolator {
+	return pom.Interpolator()
+}
+
+// logger 返回解析器使用的日志记录器，未设置时返回不输出的日志记录器
+func (r *PomResolver) logger() *zap.Logger {
+	return loggerOrNop(r.Logger)
+}
+
+// Interpolator 返回基于有效 POM 属性、模型引用与解析时用户属性的插值器，与解析器内部使用的插值器一致
+func (p *EffectivePom) Interpolator() *Interpolator {
+	i := NewInterpolator(p.Properties)
+	i.Model = p.Model
+	if p.UserProperties != nil {
+		i.UserProperties = p.UserProperties
+	}
+	return i
+}
+
+// importBoms 按 Maven 的规则导入 BOM 中的依赖管理
+// 直接声明（包括继承）的依赖管理优先于导入的 BOM；多个 BOM 之间先声明者优先，
+// 子 POM 中导入的 BOM 先于父 POM 中导入的 BOM
+func (r *PomResolver) importBoms(pom *EffectivePom, imports []ManagedDependency, resolving map[string]bool) {
+	depth := map[string]int{}
+	for idx, source := range pom.Chain() {
+		depth[source.Path] = idx
+	}
+	sort.SliceStable(imports, func(i, j int) bool {
+		return depth[imports[i].Source.Path] < depth[imports[j].Source.Path]
+	})
+
+	for _, imp := range imports {
+		bom := r.resolveBom(imp.Coordinate, resolving)
+		if bom == nil {
+			continue
+		}
+		bomSource := bom.Source()
+		for name, m := range bom.Managed {
+			if _, ok := pom.Managed[name]; ok {
+				continue
+			}
+			// BOM 自身又导入了其他 BOM 时，保留最内层的 BOM 坐标
+			if m.Bom == nil {
+				m.Bom = &bomSource
+			}
+			pom.Managed[name] = m
+		}
+	}
+}
+
+// resolveBom 从本地仓库中加载 BOM 并生成有效 POM，找不到时返回 nil
+func (r *PomResolver) resolveBom(c Coordinate, resolving map[string]bool) *EffectivePom {
+	if r.LocalRepository == nil {
+		return nil
+	}
+	bomPath, ok := r.LocalRepository.FindPom(c)
+	if !ok {
+		r.logger().Warn("未在本地仓库中找到 BOM", zap.Stringer("bom", c))
+		return nil
+	}
+	bom, err := r.resolve(bomPath, resolving)
+	if err != nil {
+		r.logger().Warn("解析 BOM 时出错", zap.String("path", bomPath), zap.Error(err))
+		return nil
+	}
+	return bom
+}
+
+// managedDependency 将依赖声明转换为 Dependency，应用 dependencyManagement 并返回版本来源
+func (p *EffectivePom) managedDependency(logger *zap.Logger, i *Interpolator, decl dependencyDecl) (Dependency, VersionSource) {
+	dep := decl.dependency
+	d := Dependency{
+		Coordinate: interpolateCoordinate(logger, i, Coordinate{
+			GroupId:    deref(dep.GroupID),
+			ArtifactId: deref(dep.ArtifactID),
+			Version:    deref(dep.Version),
+			Type:       deref(dep.Type),
+			Classifier: deref(dep.Classifier),
+		}),
+		Scope:    interpolate(logger, i, deref(dep.Scope)),
+		Children: []Dependency{},
+	}
+
+	var source VersionSource
+	if d.Version != "" {
+		source = p.versionSource(decl.source, nil)
+	}
+	if m, ok := p.Managed[d.ManagementKey()]; ok {
+		if d.Version == "" {
+			d.Version = m.Version
+			source = p.versionSource(m.Source, m.Bom)
+		}
+		if d.Scope == "" {
+			d.Scope = m.Scope
+		}
+	}
+	if d.Scope == "" {
+		d.Scope = "compile"
+	}
+	return d, source
+}
+
+// versionSource 根据声明版本的 POM 判断版本来源类型
+func (p *EffectivePom) versionSource(declaredBy PomSource, bom *PomSource) VersionSource {
+	switch {
+	case bom != nil:
+		return VersionSource{Kind: VersionFromBom, Source: *bom}
+	case declaredBy.Path == p.Path:
+		return VersionSource{Kind: VersionFromSelf, Source: declaredBy}
+	default:
+		return VersionSource{Kind: VersionFromParent, Source: declaredBy}
+	}
+}
+
+// interpolate 对字符串进行属性插值，出错时记录日志并返回尽可能完成插值的结果
+func interpolate(logger *zap.Logger, i *Interpolator, s string) string {
+	v, err := i.Interpolate(s)
+	if err != nil {
+		logger.Warn("属性插值时出错", zap.String("value", s), zap.Error(err))
+	}
+	return v
+}
+
+// interpolateCoordinate 对坐标的每个字段进行属性插值
+func interpolateCoordinate(logger *zap.Logger, i *Interpolator, c Coordinate) Coordinate {
+	rs, err := c.Interpolate(i)
+	if err != nil {
+		logger.Warn("坐标插值时出错", zap.Stringer("coordinate", c), zap.Error(err))
+	}
+	return rs
+}
+
+// deref 返回字符串指针指向的值，指针为 nil 时返回空字符串
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
@@ -0,0 +1,466 @@
+package pom_component_parsing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// writeTestFile 在临时目录中写入测试文件，自动创建父目录
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	return path
+}
+
+const testParentPom = `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>app</module>
+  </modules>
+  <properties>
+    <guava.version>32.1.2-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${guava.version}</version>
+      </dependency>
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>4.13.2</version>
+        <scope>test</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+const testAppPom = `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>common</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+func TestPomResolver_ResolveReactor(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	poms, err := NewPomResolver().ResolveReactor(dir)
+	if err != nil {
+		t.Fatalf("ResolveReactor() error = %v", err)
+	}
+	if len(poms) != 2 {
+		t.Fatalf("ResolveReactor() 返回 %d 个模块, want 2", len(poms))
+	}
+
+	app := poms[1]
+	wantCoord := Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"}
+	if app.Coordinate != wantCoord {
+		t.Errorf("Coordinate = %v, want %v", app.Coordinate, wantCoord)
+	}
+
+	want := []Dependency{
+		{Coordinate: Coordinate{GroupId: "com.google.guava", ArtifactId: "guava", Version: "32.1.2-jre"}, Scope: "compile", Children: []Dependency{}},
+		{Coordinate: Coordinate{GroupId: "junit", ArtifactId: "junit", Version: "4.13.2"}, Scope: "test", Children: []Dependency{}},
+		{Coordinate: Coordinate{GroupId: "com.example", ArtifactId: "common", Version: "1.0.0"}, Scope: "compile", Children: []Dependency{}},
+	}
+	if !reflect.DeepEqual(app.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", app.Dependencies, want)
+	}
+}
+
+func TestEffectivePom_Interpolator_UserProperties(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	r := NewPomResolver()
+	r.UserProperties["guava.version"] = "33.0.0-jre"
+	poms, err := r.ResolveReactor(dir)
+	if err != nil {
+		t.Fatalf("ResolveReactor() error = %v", err)
+	}
+	app := poms[1]
+	if got := app.Dependencies[0].Version; got != "33.0.0-jre" {
+		t.Fatalf("guava version = %v, want 33.0.0-jre", got)
+	}
+	// 与解析器内部使用相同的属性来源
+	if got, err := app.Interpolator().Interpolate("${guava.version}"); err != nil || got != "33.0.0-jre" {
+		t.Errorf("Interpolator().Interpolate() = %v, %v, want 33.0.0-jre", got, err)
+	}
+}
+
+func TestPomResolver_ParentCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a/pom.xml", `<project>
+  <parent><groupId>g</groupId><artifactId>b</artifactId><version>1</version><relativePath>../b/pom.xml</relativePath></parent>
+  <artifactId>a</artifactId>
+</project>`)
+	writeTestFile(t, dir, "b/pom.xml", `<project>
+  <parent><groupId>g</groupId><artifactId>a</artifactId><version>1</version><relativePath>../a/pom.xml</relativePath></parent>
+  <artifactId>b</artifactId>
+</project>`)
+
+	pom, err := NewPomResolver().Resolve(filepath.Join(dir, "a/pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	// 循环引用时父 POM 被忽略，坐标取自 parent 元素
+	wantCoord := Coordinate{GroupId: "g", ArtifactId: "a", Version: "1"}
+	if pom.Coordinate != wantCoord {
+		t.Errorf("Coordinate = %v, want %v", pom.Coordinate, wantCoord)
+	}
+}
+
+func TestScanDepsByPomResolver(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	deps, err := ScanDepsByPomResolver(dir)
+	if err != nil {
+		t.Fatalf("ScanDepsByPomResolver() error = %v", err)
+	}
+	elem, ok := deps.Get(Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"})
+	if !ok {
+		t.Fatalf("未找到 app 模块")
+	}
+	if elem.relativePath != filepath.Join("app", "pom.xml") {
+		t.Errorf("relativePath = %v, want app/pom.xml", elem.relativePath)
+	}
+	if len(elem.children) != 3 {
+		t.Errorf("children 数量 = %d, want 3", len(elem.children))
+	}
+}
+
+func TestPomResolver_ParentFromLocalRepository(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewLocalRepository(filepath.Join(dir, "repo"))
+
+	corpParent := Coordinate{GroupId: "com.corp", ArtifactId: "corp-parent", Version: "3"}
+	corpParentPath := repo.PomPath(corpParent)
+	writeTestFile(t, filepath.Dir(corpParentPath), filepath.Base(corpParentPath), `<project>
+  <groupId>com.corp</groupId>
+  <artifactId>corp-parent</artifactId>
+  <version>3</version>
+  <properties><slf4j.version>1.7.36</slf4j.version></properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-api</artifactId>
+        <version>${slf4j.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`)
+
+	project := filepath.Join(dir, "project")
+	writeTestFile(t, project, "pom.xml", `<project>
+  <parent>
+    <groupId>com.corp</groupId>
+    <artifactId>corp-parent</artifactId>
+    <version>3</version>
+  </parent>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0.0</version>
+  <properties><slf4j.version>2.0.9</slf4j.version></properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+    </dependency>
+  </dependencies>
+</project>`)
+
+	r := NewPomResolver()
+	r.LocalRepository = repo
+	pom, err := r.Resolve(filepath.Join(project, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	wantParents := []PomSource{{Coordinate: corpParent, Path: corpParentPath}}
+	if !reflect.DeepEqual(pom.Parents, wantParents) {
+		t.Errorf("Parents = %v, want %v", pom.Parents, wantParents)
+	}
+	if len(pom.Chain()) != 2 {
+		t.Errorf("Chain() 长度 = %d, want 2", len(pom.Chain()))
+	}
+
+	// 父 POM 中的依赖管理在子 POM 的上下文中插值，使用子 POM 覆盖的属性
+	managed, ok := pom.Managed["org.slf4j:slf4j-api"]
+	if !ok {
+		t.Fatalf("未找到 slf4j-api 的依赖管理")
+	}
+	if managed.Version != "2.0.9" {
+		t.Errorf("managed version = %v, want 2.0.9", managed.Version)
+	}
+	if managed.Source.Coordinate != corpParent {
+		t.Errorf("managed source = %v, want %v", managed.Source.Coordinate, corpParent)
+	}
+	if pom.Dependencies[0].Version != "2.0.9" {
+		t.Errorf("dependency version = %v, want 2.0.9", pom.Dependencies[0].Version)
+	}
+}
+
+func TestPomResolver_InheritDependencies(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency><groupId>org.projectlombok</groupId><artifactId>lombok</artifactId><version>1.18.30</version><scope>provided</scope></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.12</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`)
+	writeTestFile(t, dir, "child/pom.xml", `<project>
+  <parent><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.0.0</version></parent>
+  <artifactId>child</artifactId>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`)
+
+	pom, err := NewPomResolver().Resolve(filepath.Join(dir, "child/pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := []Dependency{
+		{Coordinate: Coordinate{GroupId: "org.projectlombok", ArtifactId: "lombok", Version: "1.18.30"}, Scope: "provided", Children: []Dependency{}},
+		{Coordinate: Coordinate{GroupId: "junit", ArtifactId: "junit", Version: "4.13.2"}, Scope: "test", Children: []Dependency{}},
+	}
+	if !reflect.DeepEqual(pom.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", pom.Dependencies, want)
+	}
+}
+
+func TestPomResolver_ImportBoms(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewLocalRepository(filepath.Join(dir, "repo"))
+
+	writeRepoPom := func(c Coordinate, body string) {
+		path := repo.PomPath(c)
+		writeTestFile(t, filepath.Dir(path), filepath.Base(path), `<project>
+  <groupId>`+c.GroupId+`</groupId>
+  <artifactId>`+c.ArtifactId+`</artifactId>
+  <version>`+c.Version+`</version>
+  <packaging>pom</packaging>
+  `+body+`
+</project>`)
+	}
+
+	bootBom := Coordinate{GroupId: "org.springframework.boot", ArtifactId: "spring-boot-dependencies", Version: "3.1.5"}
+	jacksonBom := Coordinate{GroupId: "com.fasterxml.jackson", ArtifactId: "jackson-bom", Version: "2.15.3"}
+	platformBom := Coordinate{GroupId: "com.corp", ArtifactId: "platform-bom", Version: "7"}
+
+	writeRepoPom(jacksonBom, `<dependencyManagement><dependencies>
+    <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId><version>2.15.3</version></dependency>
+  </dependencies></dependencyManagement>`)
+	writeRepoPom(bootBom, `<dependencyManagement><dependencies>
+    <dependency><groupId>com.fasterxml.jackson</groupId><artifactId>jackson-bom</artifactId><version>2.15.3</version><type>pom</type><scope>import</scope></dependency>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>2.0.9</version></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>31.0-jre</version></dependency>
+  </dependencies></dependencyManagement>`)
+	writeRepoPom(platformBom, `<dependencyManagement><dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>1.7.36</version></dependency>
+    <dependency><groupId>org.apache.commons</groupId><artifactId>commons-lang3</artifactId><version>3.13.0</version></dependency>
+  </dependencies></dependencyManagement>`)
+
+	writeTestFile(t, dir, "project/pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency><groupId>org.springframework.boot</groupId><artifactId>spring-boot-dependencies</artifactId><version>3.1.5</version><type>pom</type><scope>import</scope></dependency>
+      <dependency><groupId>com.corp</groupId><artifactId>platform-bom</artifactId><version>7</version><type>pom</type><scope>import</scope></dependency>
+      <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>32.1.2-jre</version></dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId></dependency>
+    <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId></dependency>
+    <dependency><groupId>org.apache.commons</groupId><artifactId>commons-lang3</artifactId></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version></dependency>
+  </dependencies>
+</project>`)
+
+	r := NewPomResolver()
+	r.LocalRepository = repo
+	pom, err := r.Resolve(filepath.Join(dir, "project/pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		wantVersion string
+		wantKind    VersionSourceKind
+		wantSource  Coordinate
+	}{
+		{name: "org.slf4j:slf4j-api", wantVersion: "2.0.9", wantKind: VersionFromBom, wantSource: bootBom},
+		{name: "com.google.guava:guava", wantVersion: "32.1.2-jre", wantKind: VersionFromSelf, wantSource: pom.Coordinate},
+		{name: "com.fasterxml.jackson.core:jackson-databind", wantVersion: "2.15.3", wantKind: VersionFromBom, wantSource: jacksonBom},
+		{name: "org.apache.commons:commons-lang3", wantVersion: "3.13.0", wantKind: VersionFromBom, wantSource: platformBom},
+		{name: "junit:junit", wantVersion: "4.13.2", wantKind: VersionFromSelf, wantSource: pom.Coordinate},
+	}
+
+	versions := map[string]string{}
+	for _, d := range pom.Dependencies {
+		versions[d.Name()] = d.Version
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if versions[tt.name] != tt.wantVersion {
+				t.Errorf("version = %v, want %v", versions[tt.name], tt.wantVersion)
+			}
+			source := pom.Versions[tt.name]
+			if source.Kind != tt.wantKind || source.Source.Coordinate != tt.wantSource {
+				t.Errorf("version source = %+v, want %v %v", source, tt.wantKind, tt.wantSource)
+			}
+		})
+	}
+
+	if _, ok := pom.Managed["org.springframework.boot:spring-boot-dependencies"]; ok {
+		t.Errorf("import 声明不应出现在依赖管理中")
+	}
+}
+
+func TestPomResolver_VersionRange(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewLocalRepository(filepath.Join(dir, "repo"))
+	writeTestFile(t, repo.MetadataDir("org.slf4j", "slf4j-api"), "maven-metadata-local.xml", `<metadata>
+  <versioning><versions><version>1.7.36</version><version>2.0.7</version><version>2.0.9</version></versions></versioning>
+</metadata>`)
+	writeTestFile(t, dir, "project/pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>[1.7,2.0.9)</version></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>[31.0,)</version></dependency>
+  </dependencies>
+</project>`)
+
+	r := NewPomResolver()
+	r.LocalRepository = repo
+	pom, err := r.Resolve(filepath.Join(dir, "project/pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// 本地仓库中有元数据时解析为满足范围的最高版本，否则保留原始范围
+	want := []Coordinate{
+		{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "2.0.7"},
+		{GroupId: "com.google.guava", ArtifactId: "guava", Version: "[31.0,)"},
+	}
+	for i, d := range pom.Dependencies {
+		if d.Coordinate != want[i] {
+			t.Errorf("Dependencies[%d] = %v, want %v", i, d.Coordinate, want[i])
+		}
+		if d.IsBad() {
+			t.Errorf("Dependencies[%d].IsBad() = true, want false", i)
+		}
+	}
+}
+
+func TestPomResolver_ZeroValue(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>[1.7,2.0.9)</version></dependency>
+  </dependencies>
+</project>`)
+
+	// 没有本地仓库时保留原始范围
+	deps, err := scanDepsByPomResolver(&PomResolver{}, zaptest.NewLogger(t), nil, dir)
+	if err != nil {
+		t.Fatalf("scanDepsByPomResolver() error = %v", err)
+	}
+	entries := deps.ListAllEntries()
+	if len(entries) != 1 || len(entries[0].children) != 1 || entries[0].children[0].Version != "[1.7,2.0.9)" {
+		t.Errorf("ListAllEntries() = %+v", entries)
+	}
+}
+
+func TestPomResolver_TypeAndClassifier(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency><groupId>io.netty</groupId><artifactId>netty-transport-native-epoll</artifactId><version>4.1.100.Final</version></dependency>
+      <dependency><groupId>io.netty</groupId><artifactId>netty-transport-native-epoll</artifactId><version>4.1.99.Final</version><classifier>linux-x86_64</classifier></dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency><groupId>io.netty</groupId><artifactId>netty-transport-native-epoll</artifactId></dependency>
+    <dependency><groupId>io.netty</groupId><artifactId>netty-transport-native-epoll</artifactId><classifier>linux-x86_64</classifier></dependency>
+    <dependency><groupId>com.example</groupId><artifactId>core</artifactId><version>1.0.0</version></dependency>
+    <dependency><groupId>com.example</groupId><artifactId>core</artifactId><version>1.0.0</version><type>test-jar</type><scope>test</scope></dependency>
+  </dependencies>
+</project>`)
+
+	pom, err := NewPomResolver().Resolve(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := []string{
+		"io.netty:netty-transport-native-epoll:4.1.100.Final",
+		"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.99.Final",
+		"com.example:core:1.0.0",
+		"com.example:core:test-jar:1.0.0",
+	}
+	var got []string
+	for _, d := range pom.Dependencies {
+		got = append(got, d.Coordinate.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,301 @@
+package pom_component_parsing
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+// ActivationContext 评估 profile 激活条件所需的环境信息
+// 与 Maven 一致，同一个 activation 中的多个条件需要同时满足
+type ActivationContext struct {
+	JdkVersion       string            // JDK 版本，如 1.8.0_292、17.0.2，为空时 jdk 条件均不满足
+	OsName           string            // 操作系统名称，与 Java 的 os.name 一致
+	OsArch           string            // 操作系统架构，与 Java 的 os.arch 一致
+	OsVersion        string            // 操作系统版本，与 Java 的 os.version 一致，为空时 os.version 条件不满足
+	Properties       map[string]string // 系统属性与用户属性（-D），用于 property 条件
+	ExplicitProfiles []string          // 显式指定的 profile，相当于 mvn -P，以 ! 或 - 开头表示禁用
+}
+
+// NewActivationContext 使用当前系统信息创建激活上下文
+func NewActivationContext(jdkVersion string) ActivationContext {
+	return ActivationContext{
+		JdkVersion: jdkVersion,
+		OsName:     javaOsName(),
+		OsArch:     javaOsArch(),
+		Properties: defaultSystemProperties(),
+	}
+}
+
+// ActiveProfiles 返回 POM 中处于激活状态的 profile，baseDir 为 POM 所在目录
+// activeByDefault 的 profile 只有在同一个 POM 中没有其他 profile 被激活时才生效
+func (c ActivationContext) ActiveProfiles(project *gopom.Project, baseDir string) []gopom.Profile {
+	if project == nil || project.Profiles == nil {
+		return nil
+	}
+
+	explicit, disabled := c.explicitProfiles()
+
+	var active, byDefault []gopom.Profile
+	for _, profile := range *project.Profiles {
+		id := profileID(profile)
+		if disabled[id] {
+			continue
+		}
+		if explicit[id] || c.IsActive(profile, baseDir) {
+			active = append(active, profile)
+			continue
+		}
+		if profile.Activation != nil && profile.Activation.ActiveByDefault != nil && *profile.Activation.ActiveByDefault {
+			byDefault = append(byDefault, profile)
+		}
+	}
+
+	if len(active) == 0 {
+		return byDefault
+	}
+	return active
+}
+
+// IsActive 判断 profile 的激活条件（jdk、os、property、file）是否满足，不考虑 activeByDefault
+// 没有声明任何条件的 profile 返回 false
+func (c ActivationContext) IsActive(profile gopom.Profile, baseDir string) bool {
+	a := profile.Activation
+	if a == nil {
+		return false
+	}
+
+	hasCondition := false
+	if a.JDK != nil {
+		hasCondition = true
+		if !c.matchJdk(deref(a.JDK)) {
+			return false
+		}
+	}
+	if a.OS != nil {
+		hasCondition = true
+		if !c.matchOs(a.OS) {
+			return false
+		}
+	}
+	if a.Property != nil {
+		hasCondition = true
+		if !c.matchProperty(a.Property) {
+			return false
+		}
+	}
+	if a.File != nil {
+		hasCondition = true
+		if !c.matchFile(a.File, baseDir) {
+			return false
+		}
+	}
+	return hasCondition
+}
+
+// explicitProfiles 将 ExplicitProfiles 拆分为显式激活与显式禁用的集合
+func (c ActivationContext) explicitProfiles() (explicit, disabled map[string]bool) {
+	explicit, disabled = map[string]bool{}, map[string]bool{}
+	for _, p := range c.ExplicitProfiles {
+		for _, id := range strings.Split(p, ",") {
+			id = strings.TrimSpace(id)
+			switch {
+			case id == "":
+			case strings.HasPrefix(id, "!"), strings.HasPrefix(id, "-"):
+				disabled[id[1:]] = true
+			case strings.HasPrefix(id, "+"):
+				explicit[id[1:]] = true
+			default:
+				explicit[id] = true
+			}
+		}
+	}
+	return explicit, disabled
+}
+
+// matchJdk 评估 jdk 条件，支持前缀匹配（1.8）、取反（!1.8）与版本范围（[1.8,11)）
+func (c ActivationContext) matchJdk(jdk string) bool {
+	if c.JdkVersion == "" || jdk == "" {
+		return false
+	}
+	if strings.HasPrefix(jdk, "!") {
+		return !strings.HasPrefix(c.JdkVersion, jdk[1:])
+	}
+	if strings.HasPrefix(jdk, "[") || strings.HasPrefix(jdk, "(") {
+		return matchJdkRanges(c.JdkVersion, jdk)
+	}
+	return strings.HasPrefix(c.JdkVersion, jdk)
+}
+
+// matchOs 评估 os 条件，name、family、arch、version 均支持 ! 取反
+func (c ActivationContext) matchOs(o *gopom.ActivationOS) bool {
+	match := func(want string, test func(string) bool) bool {
+		if want == "" {
+			return true
+		}
+		if strings.HasPrefix(want, "!") {
+			return !test(want[1:])
+		}
+		return test(want)
+	}
+	equalFold := func(actual string) func(string) bool {
+		return func(want string) bool { return strings.EqualFold(actual, want) }
+	}
+
+	return match(deref(o.Name), equalFold(c.OsName)) &&
+		match(deref(o.Family), c.isOsFamily) &&
+		match(deref(o.Arch), equalFold(c.OsArch)) &&
+		match(deref(o.Version), equalFold(c.OsVersion))
+}
+
+// isOsFamily 判断当前操作系统是否属于指定的系列，规则与 plexus-utils 的 Os 类一致
+func (c ActivationContext) isOsFamily(family string) bool {
+	name := strings.ToLower(c.OsName)
+	windows := strings.Contains(name, "windows")
+	mac := strings.Contains(name, "mac")
+
+	switch strings.ToLower(family) {
+	case "windows":
+		return windows
+	case "mac":
+		return mac
+	case "unix":
+		// Mac OS X 同时属于 unix 系列
+		return !windows && (!mac || strings.HasSuffix(name, "x"))
+	case "dos":
+		return windows
+	default:
+		return strings.Contains(name, strings.ToLower(family))
+	}
+}
+
+// matchProperty 评估 property 条件
+// 只有 name 时判断属性是否存在，name 以 ! 开头表示属性不存在；value 以 ! 开头表示取值不相等
+func (c ActivationContext) matchProperty(p *gopom.ActivationProperty) bool {
+	name := deref(p.Name)
+	if name == "" {
+		return false
+	}
+
+	reverseName := strings.HasPrefix(name, "!")
+	name = strings.TrimPrefix(name, "!")
+	actual := c.Properties[name]
+
+	value := deref(p.Value)
+	if value == "" {
+		return (actual != "") != reverseName
+	}
+	if strings.HasPrefix(value, "!") {
+		return actual != value[1:]
+	}
+	return actual == value
+}
+
+// matchFile 评估 file 条件，路径中可以使用 ${basedir}、${project.basedir} 以及系统属性
+func (c ActivationContext) matchFile(f *gopom.ActivationFile, baseDir string) bool {
+	i := NewInterpolator(nil)
+	i.SystemProperties = c.Properties
+	i.Model["basedir"] = baseDir
+	i.Model["project.basedir"] = baseDir
+
+	exists := func(path string) bool {
+		path, _ = i.Interpolate(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	if path := deref(f.Exists); path != "" {
+		return exists(path)
+	}
+	if path := deref(f.Missing); path != "" {
+		return !exists(path)
+	}
+	return false
+}
+
+// jdkRangeRegexp 匹配版本范围中的单个区间，如 [1.8,11)、(,1.8]、[17]
+var jdkRangeRegexp = regexp.MustCompile(`([\[(])([^\[\]()]*)([\])])`)
+
+// matchJdkRanges 判断 JDK 版本是否落在任意一个区间内
+func matchJdkRanges(version, ranges string) bool {
+	for _, m := range jdkRangeRegexp.FindAllStringSubmatch(ranges, -1) {
+		lower, upper, hasComma := strings.Cut(m[2], ",")
+		lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+		if !hasComma {
+			// [17] 表示精确匹配，使用前缀比较以兼容 17.0.2
+			if strings.HasPrefix(version, lower) {
+				return true
+			}
+			continue
+		}
+
+		ok := true
+		if lower != "" {
+			cmp := compareJdkVersion(version, lower)
+			ok = cmp > 0 || (cmp == 0 && m[1] == "[")
+		}
+		if ok && upper != "" {
+			cmp := compareJdkVersion(version, upper)
+			ok = cmp < 0 || (cmp == 0 && m[3] == "]")
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compareJdkVersion 按数字逐段比较 JDK 版本，只比较双方都存在的段，使 11 与 11.0.2 相等
+func compareJdkVersion(a, b string) int {
+	pa, pb := jdkVersionParts(a), jdkVersionParts(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// jdkVersionParts 将 JDK 版本拆分为数字段，如 1.8.0_292 -> [1 8 0 292]
+func jdkVersionParts(v string) []int {
+	var parts []int
+	for _, s := range strings.FieldsFunc(v, func(r rune) bool { return r == '.' || r == '_' || r == '-' || r == '+' }) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// javaOsArch 返回与 Java os.arch 系统属性一致的架构名称
+func javaOsArch() string {
+	switch runtime.GOARCH {
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "x86"
+	default:
+		return runtime.GOARCH
+	}
+}
+
+// profileID 返回 profile 的 ID，未声明 ID 时与 Maven 一致使用 default
+func profileID(profile gopom.Profile) string {
+	if id := deref(profile.ID); id != "" {
+		return id
+	}
+	return "default"
+}
@@ -0,0 +1,218 @@
+package pom_component_parsing
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/liwenson/pom_component_parsing/utils"
+	"github.com/vifraa/gopom"
+)
+
+// parseTestProject 从字符串解析 gopom.Project
+func parseTestProject(t *testing.T, content string) *gopom.Project {
+	t.Helper()
+	project, err := gopom.ParseFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("解析 pom 失败: %v", err)
+	}
+	return project
+}
+
+func TestActivationContext_MatchJdk(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		jdk     string
+		want    bool
+	}{
+		{name: "前缀匹配 1.8", version: "1.8.0_292", jdk: "1.8", want: true},
+		{name: "前缀不匹配", version: "17.0.2", jdk: "1.8", want: false},
+		{name: "取反", version: "17.0.2", jdk: "!1.8", want: true},
+		{name: "范围内", version: "11.0.11", jdk: "[1.8,17)", want: true},
+		{name: "范围上界开区间", version: "17.0.2", jdk: "[1.8,17)", want: false},
+		{name: "范围下界无限", version: "1.8.0_292", jdk: "(,11)", want: true},
+		{name: "范围上界无限", version: "21", jdk: "[17,)", want: true},
+		{name: "多个区间", version: "21.0.1", jdk: "(,1.8],[21,)", want: true},
+		{name: "JDK 版本未知", version: "", jdk: "1.8", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ActivationContext{JdkVersion: tt.version}
+			if got := c.matchJdk(tt.jdk); got != tt.want {
+				t.Errorf("matchJdk(%q) = %v, want %v", tt.jdk, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivationContext_MatchOs(t *testing.T) {
+	linux := ActivationContext{OsName: "Linux", OsArch: "amd64"}
+	mac := ActivationContext{OsName: "Mac OS X", OsArch: "aarch64"}
+	windows := ActivationContext{OsName: "Windows", OsArch: "amd64"}
+
+	tests := []struct {
+		name string
+		ctx  ActivationContext
+		os   gopom.ActivationOS
+		want bool
+	}{
+		{name: "linux 属于 unix", ctx: linux, os: gopom.ActivationOS{Family: utils.String("unix")}, want: true},
+		{name: "mac 属于 unix", ctx: mac, os: gopom.ActivationOS{Family: utils.String("unix")}, want: true},
+		{name: "mac 属于 mac", ctx: mac, os: gopom.ActivationOS{Family: utils.String("mac")}, want: true},
+		{name: "windows 不属于 unix", ctx: windows, os: gopom.ActivationOS{Family: utils.String("unix")}, want: false},
+		{name: "取反 family", ctx: linux, os: gopom.ActivationOS{Family: utils.String("!windows")}, want: true},
+		{name: "名称忽略大小写", ctx: linux, os: gopom.ActivationOS{Name: utils.String("linux")}, want: true},
+		{name: "架构不匹配", ctx: mac, os: gopom.ActivationOS{Family: utils.String("mac"), Arch: utils.String("amd64")}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ctx.matchOs(&tt.os); got != tt.want {
+				t.Errorf("matchOs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivationContext_MatchProperty(t *testing.T) {
+	c := ActivationContext{Properties: map[string]string{"env": "prod", "skipTests": "true"}}
+
+	tests := []struct {
+		name string
+		prop gopom.ActivationProperty
+		want bool
+	}{
+		{name: "属性存在", prop: gopom.ActivationProperty{Name: utils.String("skipTests")}, want: true},
+		{name: "属性不存在", prop: gopom.ActivationProperty{Name: utils.String("release")}, want: false},
+		{name: "要求属性不存在", prop: gopom.ActivationProperty{Name: utils.String("!release")}, want: true},
+		{name: "取值相等", prop: gopom.ActivationProperty{Name: utils.String("env"), Value: utils.String("prod")}, want: true},
+		{name: "取值不相等", prop: gopom.ActivationProperty{Name: utils.String("env"), Value: utils.String("dev")}, want: false},
+		{name: "取值取反", prop: gopom.ActivationProperty{Name: utils.String("env"), Value: utils.String("!dev")}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.matchProperty(&tt.prop); got != tt.want {
+				t.Errorf("matchProperty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivationContext_MatchFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "src/main/docker/Dockerfile", "FROM scratch")
+	c := ActivationContext{Properties: map[string]string{}}
+
+	tests := []struct {
+		name string
+		file gopom.ActivationFile
+		want bool
+	}{
+		{name: "相对路径存在", file: gopom.ActivationFile{Exists: utils.String("src/main/docker/Dockerfile")}, want: true},
+		{name: "basedir 路径存在", file: gopom.ActivationFile{Exists: utils.String("${basedir}/src/main/docker/Dockerfile")}, want: true},
+		{name: "文件不存在", file: gopom.ActivationFile{Exists: utils.String("${project.basedir}/Jenkinsfile")}, want: false},
+		{name: "要求文件缺失", file: gopom.ActivationFile{Missing: utils.String("Jenkinsfile")}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.matchFile(&tt.file, dir); got != tt.want {
+				t.Errorf("matchFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const testProfilesPom = `<project>
+  <profiles>
+    <profile>
+      <id>jdk8</id>
+      <activation><jdk>1.8</jdk></activation>
+    </profile>
+    <profile>
+      <id>jdk17</id>
+      <activation><jdk>[17,)</jdk></activation>
+    </profile>
+    <profile>
+      <id>dev</id>
+      <activation><activeByDefault>true</activeByDefault></activation>
+    </profile>
+    <profile>
+      <id>release</id>
+    </profile>
+  </profiles>
+</project>`
+
+func TestActivationContext_ActiveProfiles(t *testing.T) {
+	project := parseTestProject(t, testProfilesPom)
+
+	tests := []struct {
+		name     string
+		ctx      ActivationContext
+		expected []string
+	}{
+		{name: "JDK 17 只激活 jdk17", ctx: ActivationContext{JdkVersion: "17.0.2"}, expected: []string{"jdk17"}},
+		{name: "JDK 8 只激活 jdk8", ctx: ActivationContext{JdkVersion: "1.8.0_292"}, expected: []string{"jdk8"}},
+		{name: "无其他激活时使用 activeByDefault", ctx: ActivationContext{JdkVersion: "11.0.2"}, expected: []string{"dev"}},
+		{name: "显式指定 profile", ctx: ActivationContext{JdkVersion: "11.0.2", ExplicitProfiles: []string{"release"}}, expected: []string{"release"}},
+		{name: "显式禁用 profile", ctx: ActivationContext{JdkVersion: "17.0.2", ExplicitProfiles: []string{"!jdk17"}}, expected: []string{"dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range tt.ctx.ActiveProfiles(project, "") {
+				got = append(got, profileID(p))
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ActiveProfiles() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPomResolver_ActiveProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <properties><netty.version>4.1.90.Final</netty.version></properties>
+  <dependencies>
+    <dependency><groupId>io.netty</groupId><artifactId>netty-all</artifactId><version>${netty.version}</version></dependency>
+  </dependencies>
+  <profiles>
+    <profile>
+      <id>new-netty</id>
+      <activation><property><name>newNetty</name></property></activation>
+      <properties><netty.version>4.1.100.Final</netty.version></properties>
+      <dependencies>
+        <dependency><groupId>io.netty</groupId><artifactId>netty-tcnative</artifactId><version>2.0.61.Final</version></dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>`)
+
+	r := NewPomResolver()
+	pom, err := r.Resolve(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(pom.Dependencies) != 1 || pom.Dependencies[0].Version != "4.1.90.Final" {
+		t.Errorf("未激活 profile 时 Dependencies = %v", pom.Dependencies)
+	}
+
+	r = NewPomResolver()
+	r.UserProperties["newNetty"] = "true"
+	pom, err = r.Resolve(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(pom.Dependencies) != 2 || pom.Dependencies[0].Version != "4.1.100.Final" {
+		t.Errorf("激活 profile 后 Dependencies = %v", pom.Dependencies)
+	}
+}
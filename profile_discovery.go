@@ -0,0 +1,166 @@
+package pom_component_parsing
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+	"go.uber.org/zap"
+)
+
+// ProfileInfo 表示去重后的一个 profile 及其所有声明位置
+type ProfileInfo struct {
+	ID               string               // profile ID，未声明 ID 时为 default
+	Declarations     []ProfileDeclaration // 声明该 profile 的所有位置，按发现顺序排列
+	ActiveInSettings bool                 // 是否在 settings.xml 的 activeProfiles 中列出
+}
+
+// ProfileDeclaration 表示 profile 的一处声明
+type ProfileDeclaration struct {
+	Path     string        // 声明所在的 pom.xml 或 settings.xml 路径
+	Settings bool          // 是否声明在 settings.xml 中
+	Profile  gopom.Profile // 原始的 profile 声明
+}
+
+// reactorProject 表示 reactor 中的一个原始（未合并父 POM）的项目
+type reactorProject struct {
+	path    string
+	project *gopom.Project
+}
+
+// DiscoverProfiles 查找项目中所有模块以及 settings.xml 中声明的 profile，按 ID 去重
+// settingsPath 为空时读取 ~/.m2/settings.xml 与 Maven 安装目录下的全局 settings.xml
+func DiscoverProfiles(projectDir string, settingsPath string) ([]ProfileInfo, error) {
+	projects, err := reactorProjects(zap.NewNop(), projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []ProfileInfo
+	index := map[string]int{}
+	add := func(id string) *ProfileInfo {
+		if idx, ok := index[id]; ok {
+			return &rs[idx]
+		}
+		index[id] = len(rs)
+		rs = append(rs, ProfileInfo{ID: id})
+		return &rs[len(rs)-1]
+	}
+
+	for _, p := range projects {
+		if p.project.Profiles == nil {
+			continue
+		}
+		for _, profile := range *p.project.Profiles {
+			info := add(profileID(profile))
+			info.Declarations = append(info.Declarations, ProfileDeclaration{Path: p.path, Profile: profile})
+		}
+	}
+
+	for _, settings := range loadEffectiveSettings(settingsPath) {
+		for _, profile := range settings.Profiles {
+			info := add(profileID(profile))
+			info.Declarations = append(info.Declarations, ProfileDeclaration{Path: settings.Path, Settings: true, Profile: profile})
+		}
+		for _, id := range settings.ActiveProfiles {
+			if id != "" {
+				add(id).ActiveInSettings = true
+			}
+		}
+	}
+
+	return rs, nil
+}
+
+// findActiveProfiles 返回项目所有模块与 settings.xml 中处于激活状态的 profile ID（去重）
+// settings.xml 中 activeProfiles 列出的 profile 与显式指定的 profile 同等对待
+func findActiveProfiles(logger *zap.Logger, projectDir string, settingsPath string, activation ActivationContext) ([]string, error) {
+	projects, err := reactorProjects(logger, projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	allSettings := loadEffectiveSettings(settingsPath)
+	for _, settings := range allSettings {
+		activation.ExplicitProfiles = append(activation.ExplicitProfiles, settings.ActiveProfiles...)
+	}
+
+	var rs []string
+	seen := map[string]bool{}
+	add := func(profile gopom.Profile) {
+		if id := profileID(profile); !seen[id] {
+			seen[id] = true
+			rs = append(rs, id)
+		}
+	}
+
+	for _, p := range projects {
+		for _, profile := range activation.ActiveProfiles(p.project, filepath.Dir(p.path)) {
+			add(profile)
+		}
+	}
+	for _, settings := range allSettings {
+		project := &gopom.Project{Profiles: &settings.Profiles}
+		for _, profile := range activation.ActiveProfiles(project, projectDir) {
+			add(profile)
+		}
+	}
+	return rs, nil
+}
+
+// reactorProjects 从项目根目录的 pom.xml 开始，递归解析所有模块（包括 profile 中声明的模块）
+// 根 pom.xml 解析失败时返回错误，子模块解析失败只记录日志
+func reactorProjects(logger *zap.Logger, projectDir string) ([]reactorProject, error) {
+	var rs []reactorProject
+	visited := map[string]bool{}
+
+	var walk func(pomPath string) error
+	walk = func(pomPath string) error {
+		pomPath, err := filepath.Abs(pomPath)
+		if err != nil {
+			return err
+		}
+		if visited[pomPath] {
+			return nil
+		}
+		visited[pomPath] = true
+
+		project, err := gopom.Parse(pomPath)
+		if err != nil {
+			return err
+		}
+		rs = append(rs, reactorProject{path: pomPath, project: project})
+
+		var modules []string
+		if project.Modules != nil {
+			modules = append(modules, *project.Modules...)
+		}
+		if project.Profiles != nil {
+			for _, profile := range *project.Profiles {
+				if profile.Modules != nil {
+					modules = append(modules, *profile.Modules...)
+				}
+			}
+		}
+
+		for _, module := range modules {
+			module = strings.TrimSpace(module)
+			if module == "" {
+				continue
+			}
+			modulePath := filepath.Join(filepath.Dir(pomPath), module)
+			if !strings.HasSuffix(modulePath, ".xml") {
+				modulePath = filepath.Join(modulePath, "pom.xml")
+			}
+			if err := walk(modulePath); err != nil {
+				logger.Warn("解析子模块时出错", zap.String("module", modulePath), zap.Error(err))
+			}
+		}
+		return nil
+	}
+
+	if err := walk(filepath.Join(projectDir, "pom.xml")); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
@@ -0,0 +1,118 @@
+package pom_component_parsing
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// setupProfilesProject 创建包含子模块 profile 与 settings.xml profile 的测试项目
+func setupProfilesProject(t *testing.T) (projectDir string, settingsPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	projectDir = filepath.Join(dir, "project")
+
+	writeTestFile(t, projectDir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <modules><module>core</module></modules>
+  <profiles>
+    <profile>
+      <id>jdk8</id>
+      <activation><jdk>1.8</jdk></activation>
+    </profile>
+    <profile>
+      <activation><activeByDefault>true</activeByDefault></activation>
+    </profile>
+    <profile>
+      <id>integration</id>
+      <modules><module>it</module></modules>
+    </profile>
+  </profiles>
+</project>`)
+	writeTestFile(t, projectDir, "core/pom.xml", `<project>
+  <artifactId>core</artifactId>
+  <profiles>
+    <profile>
+      <id>jdk8</id>
+      <activation><jdk>1.8</jdk></activation>
+    </profile>
+    <profile>
+      <id>native</id>
+      <activation><os><family>unix</family></os></activation>
+    </profile>
+  </profiles>
+</project>`)
+	writeTestFile(t, projectDir, "it/pom.xml", `<project>
+  <artifactId>it</artifactId>
+  <profiles>
+    <profile><id>docker</id></profile>
+  </profiles>
+</project>`)
+
+	settingsPath = writeTestFile(t, dir, "settings.xml", `<settings>
+  <profiles>
+    <profile>
+      <id>nexus</id>
+      <properties><repo.url>https://nexus.example.com</repo.url></properties>
+    </profile>
+  </profiles>
+  <activeProfiles>
+    <activeProfile>nexus</activeProfile>
+  </activeProfiles>
+</settings>`)
+
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", "")
+	return projectDir, settingsPath
+}
+
+func TestDiscoverProfiles(t *testing.T) {
+	projectDir, settingsPath := setupProfilesProject(t)
+
+	profiles, err := DiscoverProfiles(projectDir, settingsPath)
+	if err != nil {
+		t.Fatalf("DiscoverProfiles() error = %v", err)
+	}
+
+	var ids []string
+	byID := map[string]ProfileInfo{}
+	for _, p := range profiles {
+		ids = append(ids, p.ID)
+		byID[p.ID] = p
+	}
+	wantIDs := []string{"jdk8", "default", "integration", "native", "docker", "nexus"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("profile IDs = %v, want %v", ids, wantIDs)
+	}
+
+	jdk8 := byID["jdk8"]
+	if len(jdk8.Declarations) != 2 ||
+		jdk8.Declarations[0].Path != filepath.Join(projectDir, "pom.xml") ||
+		jdk8.Declarations[1].Path != filepath.Join(projectDir, "core", "pom.xml") {
+		t.Errorf("jdk8 declarations = %+v", jdk8.Declarations)
+	}
+
+	nexus := byID["nexus"]
+	if !nexus.ActiveInSettings || len(nexus.Declarations) != 1 || !nexus.Declarations[0].Settings || nexus.Declarations[0].Path != settingsPath {
+		t.Errorf("nexus = %+v", nexus)
+	}
+}
+
+func TestFindActiveProfiles(t *testing.T) {
+	projectDir, settingsPath := setupProfilesProject(t)
+
+	activation := ActivationContext{JdkVersion: "17.0.2", OsName: "Linux", Properties: map[string]string{}}
+	got, err := findActiveProfiles(zaptest.NewLogger(t), projectDir, settingsPath, activation)
+	if err != nil {
+		t.Fatalf("findActiveProfiles() error = %v", err)
+	}
+
+	want := []string{"default", "native", "nexus"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findActiveProfiles() = %v, want %v", got, want)
+	}
+}
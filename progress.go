@@ -0,0 +1,167 @@
+package pom_component_parsing
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressPhase 表示扫描进度事件所处的阶段
+type ProgressPhase string
+
+const (
+	ProgressEnvironmentCheck    ProgressPhase = "environment-check"    // 检查 Maven 与 Java 环境完成，只在使用需要 Maven 的策略时发出
+	ProgressProfileDiscovery    ProgressPhase = "profile-discovery"    // 评估 profile 的激活条件完成
+	ProgressMavenStarted        ProgressPhase = "maven-started"        // Maven 命令已启动
+	ProgressModuleResolved      ProgressPhase = "module-resolved"      // 一个模块的依赖解析完成
+	ProgressArtifactDownloading ProgressPhase = "artifact-downloading" // Maven 开始从远程仓库下载文件
+	ProgressGraphFileParsed     ProgressPhase = "graph-file-parsed"    // 一个模块的结果文件解析完成
+	ProgressDone                ProgressPhase = "done"                 // 整个扫描结束
+)
+
+// ProgressEvent 是扫描过程中的一个进度事件，各阶段只设置与其相关的字段
+type ProgressEvent struct {
+	Phase ProgressPhase
+	Time  time.Time // 事件发生的时间
+	// Duration 是该阶段的耗时：环境检查、profile 评估、单个模块的构建、单个结果文件的解析或整个扫描，其他阶段为 0
+	Duration time.Duration
+	Strategy ScanStrategy // 产生事件的扫描策略，done 为最终使用的策略，环境检查时为空
+	// Module 是模块名：来自 Maven 输出时为 artifactId（无法确定时为 Maven 显示的模块名称），
+	// 来自结果文件或 POM 解析时为模块 pom.xml 相对于项目根目录的路径
+	Module string
+	// Index 与 Total 是当前模块在 reactor 中的序号与模块总数，或当前结果文件的序号与结果文件总数，从 1 开始，未知时为 0
+	Index      int
+	Total      int
+	Artifact   string   // 正在下载的文件地址
+	Repository string   // 下载所用的仓库 id
+	Path       string   // 解析的结果文件路径
+	Profiles   []string // 按激活条件评估后处于激活状态的 profile，只用于报告，不会通过 -P 传给 Maven
+	Err        error    // 该阶段的错误，done 时为扫描返回的错误
+}
+
+// ProgressFunc 接收扫描进度事件。Maven 的标准输出与标准错误输出由不同的 goroutine 处理，
+// 但同一次扫描中的事件按发生顺序串行调用，回调中不应执行耗时的操作
+type ProgressFunc func(ProgressEvent)
+
+// emit 补全事件时间后调用 f，f 为空时忽略
+func (f ProgressFunc) emit(e ProgressEvent) {
+	if f == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	f(e)
+}
+
+// withStrategy 返回为没有策略的事件填充 strategy 的 ProgressFunc，f 为空时返回 nil
+func (f ProgressFunc) withStrategy(strategy ScanStrategy) ProgressFunc {
+	if f == nil {
+		return nil
+	}
+	return func(e ProgressEvent) {
+		if e.Strategy == "" {
+			e.Strategy = strategy
+		}
+		f(e)
+	}
+}
+
+var (
+	// logLevelPattern 匹配 Maven 日志行的级别前缀
+	logLevelPattern = regexp.MustCompile(`^\[(?:INFO|WARNING|WARN|ERROR|FATAL|DEBUG)\]\s*`)
+	// moduleHeaderPattern 匹配 Maven 3.6 起在每个模块开始时输出的 "----< groupId:artifactId >----"
+	moduleHeaderPattern = regexp.MustCompile(`^-+< (\S+) >-+$`)
+	// moduleBuildingPattern 匹配 "Building app 1.0.0    [2/5]"，单模块项目没有 [序号/总数]；
+	// 排除插件输出的 "Building jar: /path/app.jar"
+	moduleBuildingPattern = regexp.MustCompile(`^Building ([^:\[]+?)(?:\s+\[(\d+)/(\d+)\])?$`)
+	// downloadingPattern 匹配 "Downloading from central: https://..."，以及 Maven 3.5 之前的 "Downloading: https://..."
+	downloadingPattern = regexp.MustCompile(`^Downloading(?: from (\S+))?: (\S+)$`)
+	// reactorEndPattern 匹配所有模块构建结束后输出的汇总信息
+	reactorEndPattern = regexp.MustCompile(`^(?:Reactor Summary|BUILD (?:SUCCESS|FAILURE))`)
+)
+
+// reactorProgress 解析 Maven 的 reactor 输出，在模块构建完成与下载文件时发出进度事件，
+// 可以被标准输出与标准错误输出并发调用
+type reactorProgress struct {
+	mu       sync.Mutex
+	progress ProgressFunc
+
+	started bool      // 是否已发出 maven-started 事件
+	header  string    // 最近一个模块标题中的 groupId:artifactId，模块开始后清空
+	module  string    // 正在构建的模块，没有时为空
+	index   int       // 正在构建的模块的序号
+	total   int       // reactor 中的模块总数
+	start   time.Time // 正在构建的模块的开始时间
+}
+
+// begin 发出 maven-started 事件，Maven 的输出可能先于 begin 被处理，事件只发出一次
+func (r *reactorProgress) begin() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emitStarted()
+}
+
+// emitStarted 在还没有发出 maven-started 事件时发出，调用时需持有 r.mu
+func (r *reactorProgress) emitStarted() {
+	if r.started {
+		return
+	}
+	r.started = true
+	r.progress.emit(ProgressEvent{Phase: ProgressMavenStarted})
+}
+
+// add 解析一行 Maven 输出
+func (r *reactorProgress) add(line string) {
+	if r.progress == nil {
+		return
+	}
+	line = strings.TrimSpace(ansiPattern.ReplaceAllString(line, ""))
+	line = strings.TrimSpace(logLevelPattern.ReplaceAllString(line, ""))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emitStarted()
+	if m := moduleHeaderPattern.FindStringSubmatch(line); m != nil {
+		r.finish()
+		r.header = m[1]
+		return
+	}
+	if m := moduleBuildingPattern.FindStringSubmatch(line); m != nil {
+		// Maven 3.6 之前没有模块标题，以 Building 行作为上一个模块的结束
+		r.finish()
+		r.module = strings.TrimSpace(m[1])
+		if _, artifactId, ok := strings.Cut(r.header, ":"); ok {
+			r.module = artifactId
+		}
+		r.header = ""
+		r.index, _ = strconv.Atoi(m[2])
+		r.total, _ = strconv.Atoi(m[3])
+		r.start = time.Now()
+		return
+	}
+	if m := downloadingPattern.FindStringSubmatch(line); m != nil {
+		r.progress.emit(ProgressEvent{Phase: ProgressArtifactDownloading, Module: r.module, Artifact: m[2], Repository: m[1]})
+		return
+	}
+	if reactorEndPattern.MatchString(line) {
+		r.finish()
+	}
+}
+
+// finish 为正在构建的模块发出 module-resolved 事件，调用时需持有 r.mu
+func (r *reactorProgress) finish() {
+	if r.module == "" {
+		return
+	}
+	r.progress.emit(ProgressEvent{
+		Phase:    ProgressModuleResolved,
+		Duration: time.Since(r.start),
+		Module:   r.module,
+		Index:    r.index,
+		Total:    r.total,
+	})
+	r.module = ""
+}
@@ -0,0 +1,118 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mavenReactorLog 是多模块项目执行 depgraph:graph 时 Maven 3.9 的输出
+const mavenReactorLog = `[INFO] Scanning for projects...
+[INFO] ------------------------------------------------------------------------
+[INFO] Reactor Build Order:
+[INFO]
+[INFO] parent                                                             [pom]
+[INFO] app                                                                [jar]
+[INFO]
+[INFO] -----------------------< com.example:parent >-----------------------
+[INFO] Building parent 1.0.0                                              [1/2]
+[INFO] --------------------------------[ pom ]---------------------------------
+[INFO] Downloading from central: https://repo.maven.apache.org/maven2/com/github/ferstl/depgraph-maven-plugin/4.0.1/depgraph-maven-plugin-4.0.1.pom
+[INFO] Downloaded from central: https://repo.maven.apache.org/maven2/com/github/ferstl/depgraph-maven-plugin/4.0.1/depgraph-maven-plugin-4.0.1.pom (12 kB at 34 kB/s)
+[INFO]
+[INFO] --- depgraph:4.0.1:graph (default-cli) @ parent ---
+[INFO] -------------------------< com.example:app >-------------------------
+[INFO] Building App Module 1.0.0                                          [2/2]
+[INFO] --------------------------------[ jar ]---------------------------------
+[INFO] Downloading: https://nexus.example.com/repository/public/com/foo/bar/1.2/bar-1.2.pom
+[INFO] Building jar: /tmp/app/target/app-1.0.0.jar
+[INFO] ------------------------------------------------------------------------
+[INFO] Reactor Summary for parent 1.0.0:
+[INFO] BUILD SUCCESS
+`
+
+func TestReactorProgress(t *testing.T) {
+	var got []ProgressEvent
+	r := reactorProgress{progress: func(e ProgressEvent) {
+		if e.Time.IsZero() {
+			t.Errorf("事件 %v 没有时间", e.Phase)
+		}
+		e.Time, e.Duration = time.Time{}, 0
+		got = append(got, e)
+	}}
+	for _, line := range strings.Split(mavenReactorLog, "\n") {
+		r.add(line)
+	}
+
+	want := []ProgressEvent{
+		{Phase: ProgressMavenStarted},
+		{
+			Phase:      ProgressArtifactDownloading,
+			Module:     "parent",
+			Artifact:   "https://repo.maven.apache.org/maven2/com/github/ferstl/depgraph-maven-plugin/4.0.1/depgraph-maven-plugin-4.0.1.pom",
+			Repository: "central",
+		},
+		{Phase: ProgressModuleResolved, Module: "parent", Index: 1, Total: 2},
+		{Phase: ProgressArtifactDownloading, Module: "app", Artifact: "https://nexus.example.com/repository/public/com/foo/bar/1.2/bar-1.2.pom"},
+		{Phase: ProgressModuleResolved, Module: "app", Index: 2, Total: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("事件 = %+v, want %+v", got, want)
+	}
+}
+
+func TestReactorProgress_SingleModule(t *testing.T) {
+	var got []ProgressEvent
+	r := reactorProgress{progress: func(e ProgressEvent) { got = append(got, e) }}
+	// Maven 3.6 之前没有模块标题，单模块项目没有 [序号/总数]
+	for _, line := range []string{
+		"[INFO] ------------------------------------------------------------------------",
+		"[INFO] Building app 1.0.0",
+		"[INFO] ------------------------------------------------------------------------",
+		"[INFO] BUILD SUCCESS",
+	} {
+		r.add(line)
+	}
+	if len(got) != 2 || got[1].Phase != ProgressModuleResolved || got[1].Module != "app 1.0.0" || got[1].Total != 0 {
+		t.Errorf("事件 = %+v", got)
+	}
+}
+
+func TestScanMavenProjectWithOptions_Progress(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	var phases []ProgressPhase
+	var resolved []ProgressEvent
+	var done ProgressEvent
+	opts := ScanOptions{Strategies: []ScanStrategy{ScanByPomResolver}}
+	opts.Progress = func(e ProgressEvent) {
+		phases = append(phases, e.Phase)
+		switch e.Phase {
+		case ProgressModuleResolved:
+			resolved = append(resolved, e)
+		case ProgressDone:
+			done = e
+		}
+	}
+	if _, err := ScanMavenProjectWithOptions(context.Background(), dir, opts); err != nil {
+		t.Fatalf("ScanMavenProjectWithOptions() error = %v", err)
+	}
+
+	// 只使用 POM 解析器时不检查 Maven 环境
+	want := []ProgressPhase{ProgressModuleResolved, ProgressModuleResolved, ProgressDone}
+	if !reflect.DeepEqual(phases, want) {
+		t.Fatalf("阶段 = %v, want %v", phases, want)
+	}
+	for i, e := range resolved {
+		if e.Strategy != ScanByPomResolver || e.Index != i+1 || e.Total != 2 || e.Module == "" {
+			t.Errorf("module-resolved 事件 = %+v", e)
+		}
+	}
+	if done.Strategy != ScanByPomResolver || done.Total != 2 || done.Duration <= 0 || done.Err != nil {
+		t.Errorf("done 事件 = %+v", done)
+	}
+}
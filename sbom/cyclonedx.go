@@ -0,0 +1,260 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+// CycloneDXSpecVersion 是生成的 CycloneDX 文档所遵循的规范版本
+const CycloneDXSpecVersion = "1.5"
+
+// cycloneDXNamespace 是 CycloneDX 1.5 XML 文档的命名空间
+const cycloneDXNamespace = "http://cyclonedx.org/schema/bom/1.5"
+
+// CycloneDX 组件类型与作用域
+const (
+	CycloneDXTypeApplication = "application"
+	CycloneDXTypeLibrary     = "library"
+
+	CycloneDXScopeRequired = "required"
+	CycloneDXScopeOptional = "optional"
+	CycloneDXScopeExcluded = "excluded"
+)
+
+// CycloneDXOptions 控制 CycloneDX 文档的生成
+type CycloneDXOptions struct {
+	Name         string    // 多个模块时顶层应用组件的名称，为空时使用第一个模块的 groupId 与名称
+	Version      string    // 多个模块时顶层应用组件的版本，为空时使用第一个模块的版本
+	SerialNumber string    // 文档序列号，形如 urn:uuid:...，为空时随机生成
+	Timestamp    time.Time // 文档生成时间，为零值时使用当前时间
+}
+
+// CycloneDXBom 表示一个 CycloneDX 1.5 文档，同时支持 JSON 与 XML 两种格式
+type CycloneDXBom struct {
+	XMLName      xml.Name              `json:"-" xml:"bom"`
+	XMLNS        string                `json:"-" xml:"xmlns,attr"`
+	BomFormat    string                `json:"bomFormat" xml:"-"`
+	SpecVersion  string                `json:"specVersion" xml:"-"`
+	SerialNumber string                `json:"serialNumber,omitempty" xml:"serialNumber,attr,omitempty"`
+	Version      int                   `json:"version" xml:"version,attr"`
+	Metadata     CycloneDXMetadata     `json:"metadata" xml:"metadata"`
+	Components   CycloneDXComponents   `json:"components,omitempty" xml:"components,omitempty"`
+	Dependencies CycloneDXDependencies `json:"dependencies,omitempty" xml:"dependencies,omitempty"`
+}
+
+// CycloneDXMetadata 表示文档的元数据，Component 描述被扫描的应用
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp" xml:"timestamp"`
+	Tools     *CycloneDXTools    `json:"tools,omitempty" xml:"tools,omitempty"`
+	Component CycloneDXComponent `json:"component" xml:"component"`
+}
+
+// CycloneDXTools 表示生成文档的工具
+type CycloneDXTools struct {
+	Components CycloneDXComponents `json:"components" xml:"components"`
+}
+
+// CycloneDXComponent 表示一个组件，字段顺序与 XML Schema 中的元素顺序一致
+type CycloneDXComponent struct {
+	Type       string              `json:"type" xml:"type,attr"`
+	BomRef     string              `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Group      string              `json:"group,omitempty" xml:"group,omitempty"`
+	Name       string              `json:"name" xml:"name"`
+	Version    string              `json:"version,omitempty" xml:"version,omitempty"`
+	Scope      string              `json:"scope,omitempty" xml:"scope,omitempty"`
+	Purl       string              `json:"purl,omitempty" xml:"purl,omitempty"`
+	Components CycloneDXComponents `json:"components,omitempty" xml:"components,omitempty"`
+}
+
+// CycloneDXComponents 是组件列表，XML 中为 <components> 包裹的 <component> 元素，列表为空时省略
+type CycloneDXComponents []CycloneDXComponent
+
+// MarshalXML 将组件列表写为 <component> 子元素
+func (l CycloneDXComponents) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Components []CycloneDXComponent `xml:"component"`
+	}{l}, start)
+}
+
+// CycloneDXDependency 表示依赖图中的一个节点
+// JSON 使用 dependsOn 字符串数组，XML 使用嵌套的 dependency 元素，两者由 BuildCycloneDX 同时填充
+type CycloneDXDependency struct {
+	Ref       string                   `json:"ref" xml:"ref,attr"`
+	DependsOn []string                 `json:"dependsOn,omitempty" xml:"-"`
+	Children  []CycloneDXDependencyRef `json:"-" xml:"dependency,omitempty"`
+}
+
+// CycloneDXDependencyRef 表示 XML 中被依赖的组件引用
+type CycloneDXDependencyRef struct {
+	Ref string `xml:"ref,attr"`
+}
+
+// CycloneDXDependencies 是依赖图节点列表，XML 中为 <dependencies> 包裹的 <dependency> 元素，列表为空时省略
+type CycloneDXDependencies []CycloneDXDependency
+
+// MarshalXML 将依赖图节点列表写为 <dependency> 子元素
+func (l CycloneDXDependencies) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Dependencies []CycloneDXDependency `xml:"dependency"`
+	}{l}, start)
+}
+
+// BuildCycloneDX 根据扫描得到的模块生成 CycloneDX 文档
+// 每个模块作为 application 组件写入 metadata，依赖作为 library 组件按 purl 去重，
+// dependencies 保留 DependencyItem.Dependencies 中完整的依赖关系
+func BuildCycloneDX(modules []model.Module, opts CycloneDXOptions) *CycloneDXBom {
+	bom := &CycloneDXBom{
+		XMLNS:        cycloneDXNamespace,
+		BomFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: opts.SerialNumber,
+		Version:      1,
+	}
+	if bom.SerialNumber == "" {
+		bom.SerialNumber = newSerialNumber()
+	}
+	timestamp := opts.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	bom.Metadata.Timestamp = timestamp.UTC().Format(time.RFC3339)
+	bom.Metadata.Tools = &CycloneDXTools{Components: CycloneDXComponents{{
+		Type: CycloneDXTypeApplication,
+		Name: ToolName,
+	}}}
+
+	graph := newDependencyGraph()
+	var apps CycloneDXComponents
+	for _, m := range modules {
+		apps = append(apps, moduleComponent(graph.nodes[graph.addModule(m)].purl))
+	}
+
+	switch {
+	case len(apps) == 1:
+		bom.Metadata.Component = apps[0]
+	case len(apps) > 1:
+		// 多个模块时使用一个顶层应用组件，各模块作为其子组件
+		root := CycloneDXComponent{
+			Type:       CycloneDXTypeApplication,
+			Name:       opts.Name,
+			Version:    opts.Version,
+			Components: apps,
+		}
+		if root.Name == "" {
+			root.Group, root.Name = apps[0].Group, apps[0].Name
+		}
+		if root.Version == "" {
+			root.Version = apps[0].Version
+		}
+		bom.Metadata.Component = root
+	}
+
+	for _, ref := range graph.order {
+		node := graph.nodes[ref]
+		if !graph.modules[ref] {
+			bom.Components = append(bom.Components, CycloneDXComponent{
+				Type:    CycloneDXTypeLibrary,
+				BomRef:  ref,
+				Group:   node.purl.Namespace,
+				Name:    node.purl.Name,
+				Version: node.purl.Version,
+				Scope:   cycloneDXScope(node.scopes),
+				Purl:    ref,
+			})
+		}
+		dep := CycloneDXDependency{Ref: ref, DependsOn: node.dependsOn}
+		for _, child := range node.dependsOn {
+			dep.Children = append(dep.Children, CycloneDXDependencyRef{Ref: child})
+		}
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+	return bom
+}
+
+// WriteJSON 以 JSON 格式写出文档
+func (b *CycloneDXBom) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(b); err != nil {
+		return fmt.Errorf("写出 CycloneDX JSON 失败: %w", err)
+	}
+	return nil
+}
+
+// WriteXML 以 XML 格式写出文档
+func (b *CycloneDXBom) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("写出 CycloneDX XML 失败: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(b); err != nil {
+		return fmt.Errorf("写出 CycloneDX XML 失败: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("写出 CycloneDX XML 失败: %w", err)
+	}
+	return nil
+}
+
+// WriteCycloneDXJSON 生成 CycloneDX 文档并以 JSON 格式写出
+func WriteCycloneDXJSON(w io.Writer, modules []model.Module, opts CycloneDXOptions) error {
+	return BuildCycloneDX(modules, opts).WriteJSON(w)
+}
+
+// WriteCycloneDXXML 生成 CycloneDX 文档并以 XML 格式写出
+func WriteCycloneDXXML(w io.Writer, modules []model.Module, opts CycloneDXOptions) error {
+	return BuildCycloneDX(modules, opts).WriteXML(w)
+}
+
+// moduleComponent 将模块转换为 application 组件，bom-ref 使用模块的 purl
+func moduleComponent(p model.PackageURL) CycloneDXComponent {
+	return CycloneDXComponent{
+		Type:    CycloneDXTypeApplication,
+		BomRef:  p.String(),
+		Group:   p.Namespace,
+		Name:    p.Name,
+		Version: p.Version,
+		Purl:    p.String(),
+	}
+}
+
+// cycloneDXScope 将 Maven 作用域转换为 CycloneDX 作用域，同一组件出现多个作用域时取最强的一个
+// test 作用域的依赖不进入运行环境，视为 excluded；provided 与 system 由运行环境提供，视为 optional
+func cycloneDXScope(scopes map[string]bool) string {
+	rs := ""
+	for scope := range scopes {
+		var s string
+		switch scope {
+		case "test":
+			s = CycloneDXScopeExcluded
+		case "provided", "system":
+			s = CycloneDXScopeOptional
+		default:
+			return CycloneDXScopeRequired
+		}
+		if rs == "" || s == CycloneDXScopeOptional {
+			rs = s
+		}
+	}
+	if rs == "" {
+		return CycloneDXScopeRequired
+	}
+	return rs
+}
+
+// newSerialNumber 随机生成 RFC 4122 第 4 版 UUID 形式的序列号
+func newSerialNumber() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
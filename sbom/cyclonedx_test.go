@@ -0,0 +1,169 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+// testModules 返回两个模块，其中 app 依赖 core，slf4j-api 在两处出现
+func testModules() []model.Module {
+	maven := model.EcoRepo{Ecosystem: "maven"}
+	slf4j := model.DependencyItem{
+		Component:  model.Component{CompName: "org.slf4j:slf4j-api", CompVersion: "2.0.9", EcoRepo: maven},
+		MavenScope: "compile",
+	}
+	return []model.Module{
+		{
+			ModuleName:     "com.example:core",
+			ModuleVersion:  "1.0.0",
+			PackageManager: "maven",
+			Dependencies: []model.DependencyItem{
+				slf4j,
+				{
+					Component:  model.Component{CompName: "junit:junit", CompVersion: "4.13.2", EcoRepo: maven},
+					MavenScope: "test",
+					Dependencies: []model.DependencyItem{{
+						Component:  model.Component{CompName: "org.hamcrest:hamcrest-core", CompVersion: "1.3", EcoRepo: maven},
+						MavenScope: "test",
+					}},
+				},
+			},
+		},
+		{
+			ModuleName:     "com.example:app",
+			ModuleVersion:  "1.0.0",
+			PackageManager: "maven",
+			Dependencies: []model.DependencyItem{
+				{
+					Component:    model.Component{CompName: "com.example:core", CompVersion: "1.0.0", EcoRepo: maven},
+					MavenScope:   "compile",
+					Dependencies: []model.DependencyItem{slf4j},
+				},
+				{
+					Component:  model.Component{CompName: "io.netty:netty-transport-native-epoll", CompVersion: "4.1.100.Final", CompClassifier: "linux-x86_64", EcoRepo: maven},
+					MavenScope: "provided",
+				},
+			},
+		},
+	}
+}
+
+func testCycloneDXOptions() CycloneDXOptions {
+	return CycloneDXOptions{
+		Name:         "example",
+		Version:      "1.0.0",
+		SerialNumber: "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+		Timestamp:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestBuildCycloneDX(t *testing.T) {
+	bom := BuildCycloneDX(testModules(), testCycloneDXOptions())
+
+	if bom.Metadata.Timestamp != "2024-01-02T03:04:05Z" {
+		t.Errorf("Metadata.Timestamp = %v", bom.Metadata.Timestamp)
+	}
+	root := bom.Metadata.Component
+	if root.Type != CycloneDXTypeApplication || root.Name != "example" || len(root.Components) != 2 {
+		t.Fatalf("Metadata.Component = %+v", root)
+	}
+	if got := root.Components[1].BomRef; got != "pkg:maven/com.example/app@1.0.0" {
+		t.Errorf("模块 bom-ref = %v", got)
+	}
+
+	// 依赖按 purl 去重，作为模块的 core 不重复出现在 components 中
+	scopes := map[string]string{}
+	for _, c := range bom.Components {
+		if c.Type != CycloneDXTypeLibrary || c.BomRef != c.Purl {
+			t.Errorf("组件 %+v 的类型或 bom-ref 不正确", c)
+		}
+		scopes[c.Purl] = c.Scope
+	}
+	wantScopes := map[string]string{
+		"pkg:maven/org.slf4j/slf4j-api@2.0.9":                                                   CycloneDXScopeRequired,
+		"pkg:maven/junit/junit@4.13.2":                                                          CycloneDXScopeExcluded,
+		"pkg:maven/org.hamcrest/hamcrest-core@1.3":                                              CycloneDXScopeExcluded,
+		"pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64": CycloneDXScopeOptional,
+	}
+	if !reflect.DeepEqual(scopes, wantScopes) {
+		t.Errorf("components = %v, want %v", scopes, wantScopes)
+	}
+
+	dependsOn := map[string][]string{}
+	for _, d := range bom.Dependencies {
+		dependsOn[d.Ref] = d.DependsOn
+	}
+	if got, want := dependsOn["pkg:maven/com.example/app@1.0.0"], []string{
+		"pkg:maven/com.example/core@1.0.0",
+		"pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("app dependsOn = %v, want %v", got, want)
+	}
+	if got, want := dependsOn["pkg:maven/junit/junit@4.13.2"], []string{"pkg:maven/org.hamcrest/hamcrest-core@1.3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("junit dependsOn = %v, want %v", got, want)
+	}
+	if _, ok := dependsOn["pkg:maven/org.hamcrest/hamcrest-core@1.3"]; !ok {
+		t.Errorf("没有依赖的组件也应出现在 dependencies 中")
+	}
+}
+
+func TestCycloneDXBom_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCycloneDXJSON(&buf, testModules()[:1], testCycloneDXOptions()); err != nil {
+		t.Fatalf("WriteCycloneDXJSON() error = %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("输出不是合法的 JSON: %v", err)
+	}
+	if doc["bomFormat"] != "CycloneDX" || doc["specVersion"] != "1.5" || doc["serialNumber"] != testCycloneDXOptions().SerialNumber {
+		t.Errorf("文档头 = %v %v %v", doc["bomFormat"], doc["specVersion"], doc["serialNumber"])
+	}
+	component := doc["metadata"].(map[string]any)["component"].(map[string]any)
+	if component["bom-ref"] != "pkg:maven/com.example/core@1.0.0" || component["type"] != "application" {
+		t.Errorf("metadata.component = %v", component)
+	}
+	if strings.Contains(buf.String(), "\\u0026") {
+		t.Errorf("purl 中的 & 不应被转义")
+	}
+}
+
+func TestCycloneDXBom_WriteXML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCycloneDXXML(&buf, testModules(), testCycloneDXOptions()); err != nil {
+		t.Fatalf("WriteCycloneDXXML() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79" version="1">`,
+		`<component type="library" bom-ref="pkg:maven/junit/junit@4.13.2">`,
+		`<scope>excluded</scope>`,
+		`<dependency ref="pkg:maven/junit/junit@4.13.2">`,
+		`<dependency ref="pkg:maven/org.hamcrest/hamcrest-core@1.3"></dependency>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML 中缺少 %s\n%s", want, out)
+		}
+	}
+
+	var decoded struct {
+		Components []struct {
+			Purl string `xml:"purl"`
+		} `xml:"components>component"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("输出不是合法的 XML: %v", err)
+	}
+	if len(decoded.Components) != 4 {
+		t.Errorf("components 数量 = %d, want 4", len(decoded.Components))
+	}
+}
@@ -0,0 +1,88 @@
+package sbom
+
+import (
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+// ToolName 是写入 SBOM 文档的生成工具名称
+const ToolName = "pom_component_parsing"
+
+// dependencyNode 表示依赖图中按 purl 去重后的一个组件
+type dependencyNode struct {
+	purl      model.PackageURL
+	component model.Component
+	scopes    map[string]bool   // 组件在依赖树中出现过的 Maven 作用域
+	dependsOn []string          // 直接依赖的 purl，按首次出现的顺序排列
+	edges     map[string]string // 直接依赖的 purl 到该依赖关系的 Maven 作用域（首次出现时的作用域）
+}
+
+// dependencyGraph 是从模块依赖树展开得到的依赖图，节点以 purl 标识
+type dependencyGraph struct {
+	nodes   map[string]*dependencyNode
+	order   []string        // 节点首次出现的顺序，保证多次生成的文档一致
+	modules map[string]bool // 属于模块（而非依赖）的节点
+}
+
+// newDependencyGraph 创建空的依赖图
+func newDependencyGraph() *dependencyGraph {
+	return &dependencyGraph{
+		nodes:   map[string]*dependencyNode{},
+		modules: map[string]bool{},
+	}
+}
+
+// moduleComponentOf 返回模块自身对应的组件，生态系统取模块的包管理器
+func moduleComponentOf(m model.Module) model.Component {
+	return model.Component{
+		CompName:    m.ModuleName,
+		CompVersion: m.ModuleVersion,
+		EcoRepo:     model.EcoRepo{Ecosystem: m.PackageManager},
+	}
+}
+
+// addModule 将模块及其完整的依赖树加入依赖图，返回模块节点的 purl
+func (g *dependencyGraph) addModule(m model.Module) string {
+	ref := g.node(moduleComponentOf(m))
+	g.modules[ref] = true
+	g.addDependencies(ref, m.Dependencies)
+	return ref
+}
+
+// addDependencies 递归加入依赖及其与父节点之间的依赖关系
+func (g *dependencyGraph) addDependencies(parent string, deps []model.DependencyItem) {
+	for _, dep := range deps {
+		ref := g.node(dep.Component)
+		if dep.MavenScope != "" {
+			g.nodes[ref].scopes[dep.MavenScope] = true
+		}
+		g.addEdge(parent, ref, dep.MavenScope)
+		g.addDependencies(ref, dep.Dependencies)
+	}
+}
+
+// node 返回组件对应的节点 purl，节点不存在时创建
+func (g *dependencyGraph) node(c model.Component) string {
+	p := c.PackageURL()
+	ref := p.String()
+	if _, ok := g.nodes[ref]; !ok {
+		c.IsDirectDependency = false
+		g.nodes[ref] = &dependencyNode{
+			purl:      p,
+			component: c,
+			scopes:    map[string]bool{},
+			edges:     map[string]string{},
+		}
+		g.order = append(g.order, ref)
+	}
+	return ref
+}
+
+// addEdge 加入 from 依赖 to 的关系，忽略重复的关系与自身依赖
+func (g *dependencyGraph) addEdge(from, to, scope string) {
+	node := g.nodes[from]
+	if _, ok := node.edges[to]; ok || from == to {
+		return
+	}
+	node.edges[to] = scope
+	node.dependsOn = append(node.dependsOn, to)
+}
@@ -0,0 +1,462 @@
+package sbom
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+// SPDXVersion 是生成的 SPDX 文档所遵循的规范版本
+const SPDXVersion = "SPDX-2.3"
+
+const (
+	spdxNoAssertion          = "NOASSERTION"                // 表示 SPDX 字段的值未知
+	spdxDocumentID           = "SPDXRef-DOCUMENT"           // 文档自身的 SPDX ID
+	spdxDocumentNamespaceURL = "https://spdx.org/spdxdocs/" // 未指定命名空间时使用的前缀
+)
+
+// SPDX 关系类型
+const (
+	SPDXRelationshipDescribes        = "DESCRIBES"
+	SPDXRelationshipContains         = "CONTAINS"
+	SPDXRelationshipDependsOn        = "DEPENDS_ON"
+	SPDXRelationshipDevDependencyOf  = "DEV_DEPENDENCY_OF"
+	SPDXRelationshipTestDependencyOf = "TEST_DEPENDENCY_OF"
+)
+
+// ArtifactFile 表示组件对应的一个文件（如 Maven 的 jar）及其校验和
+type ArtifactFile struct {
+	Name      string            // 文件名，如 slf4j-api-2.0.9.jar
+	Checksums map[string]string // 算法（SHA1、SHA256、MD5 等）到十六进制校验和的映射
+}
+
+// ArtifactLocator 返回组件对应的本地工件文件路径，如 Maven 本地仓库中的 jar，未知时返回空字符串
+type ArtifactLocator interface {
+	ComponentPath(model.Component) string
+}
+
+// LocalArtifactFiles 返回从 locator 给出的本地工件读取校验和的函数，可直接用作 SPDXOptions.Files。
+// 优先读取工件旁的 .sha1、.sha256、.md5 校验文件，没有 SHA1 校验文件但工件存在时计算其 SHA1；均不存在时返回 nil
+func LocalArtifactFiles(locator ArtifactLocator) func(model.Component) []ArtifactFile {
+	return func(component model.Component) []ArtifactFile {
+		path := locator.ComponentPath(component)
+		if path == "" {
+			return nil
+		}
+
+		checksums := map[string]string{}
+		for algorithm, ext := range map[string]string{"SHA1": ".sha1", "SHA256": ".sha256", "MD5": ".md5"} {
+			data, err := os.ReadFile(path + ext)
+			if err != nil {
+				continue
+			}
+			// 校验文件的内容可能是 "<checksum>  <文件名>"
+			if fields := strings.Fields(string(data)); len(fields) > 0 {
+				checksums[algorithm] = strings.ToLower(fields[0])
+			}
+		}
+		if checksums["SHA1"] == "" {
+			if sum, err := fileSHA1(path); err == nil {
+				checksums["SHA1"] = sum
+			}
+		}
+		if len(checksums) == 0 {
+			return nil
+		}
+		return []ArtifactFile{{Name: filepath.Base(path), Checksums: checksums}}
+	}
+}
+
+// fileSHA1 计算文件的 SHA1
+func fileSHA1(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SPDXOptions 控制 SPDX 文档的生成
+type SPDXOptions struct {
+	Name      string    // 文档名称，为空时使用第一个模块的名称
+	Namespace string    // 文档命名空间，为空时根据文档内容生成，内容不变时命名空间也不变
+	Created   time.Time // 文档生成时间，为零值时使用当前时间
+
+	// Files 返回组件对应的文件，返回 nil 表示未知
+	// 文件带有 SHA1 校验和时，对应的 package 会包含文件与 package 校验码
+	Files func(model.Component) []ArtifactFile
+}
+
+// SPDXDocument 表示一个 SPDX 2.3 文档，字段与 JSON 格式一一对应
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Files             []SPDXFile         `json:"files,omitempty"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+// SPDXCreationInfo 表示文档的创建信息
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage 表示一个 package，模块与依赖都对应一个 package
+type SPDXPackage struct {
+	SPDXID                string                `json:"SPDXID"`
+	Name                  string                `json:"name"`
+	VersionInfo           string                `json:"versionInfo,omitempty"`
+	DownloadLocation      string                `json:"downloadLocation"`
+	FilesAnalyzed         bool                  `json:"filesAnalyzed"`
+	VerificationCode      *SPDXVerificationCode `json:"packageVerificationCode,omitempty"`
+	Checksums             []SPDXChecksum        `json:"checksums,omitempty"`
+	LicenseConcluded      string                `json:"licenseConcluded"`
+	LicenseDeclared       string                `json:"licenseDeclared"`
+	CopyrightText         string                `json:"copyrightText"`
+	ExternalRefs          []SPDXExternalRef     `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string                `json:"primaryPackagePurpose,omitempty"`
+	HasFiles              []string              `json:"hasFiles,omitempty"`
+}
+
+// SPDXVerificationCode 表示 package 校验码
+type SPDXVerificationCode struct {
+	Value string `json:"packageVerificationCodeValue"`
+}
+
+// SPDXChecksum 表示一个校验和
+type SPDXChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+// SPDXExternalRef 表示 package 的外部引用，这里用于记录 purl
+type SPDXExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+// SPDXFile 表示 package 中的一个文件
+type SPDXFile struct {
+	SPDXID           string         `json:"SPDXID"`
+	FileName         string         `json:"fileName"`
+	Checksums        []SPDXChecksum `json:"checksums"`
+	LicenseConcluded string         `json:"licenseConcluded"`
+	LicenseInfo      []string       `json:"licenseInfoInFiles"`
+	CopyrightText    string         `json:"copyrightText"`
+}
+
+// SPDXRelationship 表示两个 SPDX 元素之间的关系
+type SPDXRelationship struct {
+	Element        string `json:"spdxElementId"`
+	Type           string `json:"relationshipType"`
+	RelatedElement string `json:"relatedSpdxElement"`
+}
+
+// BuildSPDX 根据扫描得到的模块生成 SPDX 文档
+// 依赖关系按 Maven 作用域转换：test 为 TEST_DEPENDENCY_OF，provided 为 DEV_DEPENDENCY_OF，其余为 DEPENDS_ON；
+// SPDX ID 由 purl 生成，同样的扫描结果总是得到同样的 ID
+func BuildSPDX(modules []model.Module, opts SPDXOptions) *SPDXDocument {
+	graph := newDependencyGraph()
+	var moduleRefs []string
+	for _, m := range modules {
+		moduleRefs = append(moduleRefs, graph.addModule(m))
+	}
+
+	doc := &SPDXDocument{
+		SPDXVersion:       SPDXVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              opts.Name,
+		DocumentNamespace: opts.Namespace,
+		Packages:          []SPDXPackage{},
+		Relationships:     []SPDXRelationship{},
+	}
+	if doc.Name == "" && len(modules) > 0 {
+		doc.Name = modules[0].ModuleName
+	}
+	if doc.DocumentNamespace == "" {
+		doc.DocumentNamespace = spdxDocumentNamespaceURL + spdxIDPart(doc.Name) + "-" + graphDigest(graph)
+	}
+	created := opts.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	doc.CreationInfo = SPDXCreationInfo{
+		Created:  created.UTC().Format(time.RFC3339),
+		Creators: []string{"Tool: " + ToolName},
+	}
+
+	ids := map[string]string{}
+	for _, ref := range graph.order {
+		ids[ref] = spdxPackageID(ref)
+	}
+	for _, ref := range moduleRefs {
+		doc.Relationships = append(doc.Relationships, SPDXRelationship{
+			Element:        spdxDocumentID,
+			Type:           SPDXRelationshipDescribes,
+			RelatedElement: ids[ref],
+		})
+	}
+
+	for _, ref := range graph.order {
+		node := graph.nodes[ref]
+		pkg := SPDXPackage{
+			SPDXID:           ids[ref],
+			Name:             node.component.CompName,
+			VersionInfo:      node.component.CompVersion,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			ExternalRefs: []SPDXExternalRef{{
+				Category: "PACKAGE-MANAGER",
+				Type:     "purl",
+				Locator:  ref,
+			}},
+			PrimaryPackagePurpose: "LIBRARY",
+		}
+		if graph.modules[ref] {
+			pkg.PrimaryPackagePurpose = "APPLICATION"
+		}
+
+		if opts.Files != nil {
+			files := opts.Files(node.component)
+			doc.Files = append(doc.Files, addSPDXFiles(&pkg, files)...)
+		}
+		for _, fileID := range pkg.HasFiles {
+			doc.Relationships = append(doc.Relationships, SPDXRelationship{
+				Element:        pkg.SPDXID,
+				Type:           SPDXRelationshipContains,
+				RelatedElement: fileID,
+			})
+		}
+		doc.Packages = append(doc.Packages, pkg)
+
+		for _, child := range node.dependsOn {
+			doc.Relationships = append(doc.Relationships, spdxDependency(ids[ref], ids[child], node.edges[child]))
+		}
+	}
+	return doc
+}
+
+// WriteJSON 以 JSON 格式写出文档
+func (d *SPDXDocument) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(d); err != nil {
+		return fmt.Errorf("写出 SPDX JSON 失败: %w", err)
+	}
+	return nil
+}
+
+// WriteTagValue 以 tag-value 格式写出文档，package 包含的文件紧跟在 package 之后
+func (d *SPDXDocument) WriteTagValue(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	tag := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(bw, "%s: %s\n", name, value)
+		}
+	}
+
+	tag("SPDXVersion", d.SPDXVersion)
+	tag("DataLicense", d.DataLicense)
+	tag("SPDXID", d.SPDXID)
+	tag("DocumentName", d.Name)
+	tag("DocumentNamespace", d.DocumentNamespace)
+	for _, creator := range d.CreationInfo.Creators {
+		tag("Creator", creator)
+	}
+	tag("Created", d.CreationInfo.Created)
+
+	files := map[string]SPDXFile{}
+	for _, f := range d.Files {
+		files[f.SPDXID] = f
+	}
+	for _, pkg := range d.Packages {
+		fmt.Fprintf(bw, "\n##### Package: %s\n\n", pkg.Name)
+		tag("PackageName", pkg.Name)
+		tag("SPDXID", pkg.SPDXID)
+		tag("PackageVersion", pkg.VersionInfo)
+		tag("PackageDownloadLocation", pkg.DownloadLocation)
+		tag("FilesAnalyzed", fmt.Sprint(pkg.FilesAnalyzed))
+		if pkg.VerificationCode != nil {
+			tag("PackageVerificationCode", pkg.VerificationCode.Value)
+		}
+		for _, c := range pkg.Checksums {
+			tag("PackageChecksum", c.Algorithm+": "+c.Value)
+		}
+		tag("PackageLicenseConcluded", pkg.LicenseConcluded)
+		tag("PackageLicenseDeclared", pkg.LicenseDeclared)
+		tag("PackageCopyrightText", pkg.CopyrightText)
+		for _, ref := range pkg.ExternalRefs {
+			tag("ExternalRef", ref.Category+" "+ref.Type+" "+ref.Locator)
+		}
+		tag("PrimaryPackagePurpose", pkg.PrimaryPackagePurpose)
+
+		for _, id := range pkg.HasFiles {
+			f := files[id]
+			fmt.Fprintln(bw)
+			tag("FileName", f.FileName)
+			tag("SPDXID", f.SPDXID)
+			for _, c := range f.Checksums {
+				tag("FileChecksum", c.Algorithm+": "+c.Value)
+			}
+			tag("LicenseConcluded", f.LicenseConcluded)
+			for _, license := range f.LicenseInfo {
+				tag("LicenseInfoInFile", license)
+			}
+			tag("FileCopyrightText", f.CopyrightText)
+		}
+	}
+
+	if len(d.Relationships) > 0 {
+		fmt.Fprintln(bw)
+	}
+	for _, r := range d.Relationships {
+		tag("Relationship", r.Element+" "+r.Type+" "+r.RelatedElement)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("写出 SPDX tag-value 失败: %w", err)
+	}
+	return nil
+}
+
+// WriteSPDXJSON 生成 SPDX 文档并以 JSON 格式写出
+func WriteSPDXJSON(w io.Writer, modules []model.Module, opts SPDXOptions) error {
+	return BuildSPDX(modules, opts).WriteJSON(w)
+}
+
+// WriteSPDXTagValue 生成 SPDX 文档并以 tag-value 格式写出
+func WriteSPDXTagValue(w io.Writer, modules []model.Module, opts SPDXOptions) error {
+	return BuildSPDX(modules, opts).WriteTagValue(w)
+}
+
+// spdxDependency 根据依赖关系的 Maven 作用域生成 SPDX 关系
+// DEV_DEPENDENCY_OF 与 TEST_DEPENDENCY_OF 的方向与 DEPENDS_ON 相反，由被依赖的 package 指向依赖方
+func spdxDependency(from, to, scope string) SPDXRelationship {
+	switch scope {
+	case "test":
+		return SPDXRelationship{Element: to, Type: SPDXRelationshipTestDependencyOf, RelatedElement: from}
+	case "provided":
+		return SPDXRelationship{Element: to, Type: SPDXRelationshipDevDependencyOf, RelatedElement: from}
+	default:
+		return SPDXRelationship{Element: from, Type: SPDXRelationshipDependsOn, RelatedElement: to}
+	}
+}
+
+// addSPDXFiles 将组件的文件加入 package，返回生成的 SPDX 文件
+// 所有文件都带有 SHA1 校验和时才计算 package 校验码，否则只记录 package 的校验和
+func addSPDXFiles(pkg *SPDXPackage, files []ArtifactFile) []SPDXFile {
+	if len(files) == 0 {
+		return nil
+	}
+
+	var sha1s []string
+	for _, f := range files {
+		if v := f.Checksums["SHA1"]; v != "" {
+			sha1s = append(sha1s, strings.ToLower(v))
+		}
+	}
+	if len(sha1s) != len(files) {
+		// 无法计算校验码时不分析文件，只把唯一文件的校验和作为 package 的校验和
+		if len(files) == 1 {
+			pkg.Checksums = spdxChecksums(files[0].Checksums)
+		}
+		return nil
+	}
+
+	var rs []SPDXFile
+	for i, f := range files {
+		file := SPDXFile{
+			SPDXID:           fmt.Sprintf("SPDXRef-File-%s-%d", strings.TrimPrefix(pkg.SPDXID, "SPDXRef-Package-"), i+1),
+			FileName:         "./" + f.Name,
+			Checksums:        spdxChecksums(f.Checksums),
+			LicenseConcluded: spdxNoAssertion,
+			LicenseInfo:      []string{spdxNoAssertion},
+			CopyrightText:    spdxNoAssertion,
+		}
+		pkg.HasFiles = append(pkg.HasFiles, file.SPDXID)
+		rs = append(rs, file)
+	}
+	pkg.FilesAnalyzed = true
+	pkg.VerificationCode = &SPDXVerificationCode{Value: spdxVerificationCode(sha1s)}
+	if len(files) == 1 {
+		pkg.Checksums = spdxChecksums(files[0].Checksums)
+	}
+	return rs
+}
+
+// spdxChecksums 将校验和按算法名称排序后转换为 SPDX 校验和
+func spdxChecksums(checksums map[string]string) []SPDXChecksum {
+	var rs []SPDXChecksum
+	for algorithm, value := range checksums {
+		if value != "" {
+			rs = append(rs, SPDXChecksum{Algorithm: strings.ToUpper(algorithm), Value: strings.ToLower(value)})
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Algorithm < rs[j].Algorithm
+	})
+	return rs
+}
+
+// spdxVerificationCode 按 SPDX 规范计算 package 校验码：将所有文件的 SHA1 排序后拼接，再计算 SHA1
+func spdxVerificationCode(sha1s []string) string {
+	sorted := append([]string(nil), sha1s...)
+	sort.Strings(sorted)
+	sum := sha1.Sum([]byte(strings.Join(sorted, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+// spdxIDInvalidChars 匹配 SPDX ID 中不允许出现的字符
+var spdxIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// spdxIDPart 将任意字符串转换为可以出现在 SPDX ID 中的形式
+func spdxIDPart(s string) string {
+	return strings.Trim(spdxIDInvalidChars.ReplaceAllString(s, "-"), "-")
+}
+
+// spdxPackageID 根据 purl 生成确定的 package SPDX ID
+// 去除非法字符后可能出现冲突，因此追加 purl 的短哈希
+func spdxPackageID(purl string) string {
+	sum := sha256.Sum256([]byte(purl))
+	return "SPDXRef-Package-" + spdxIDPart(strings.TrimPrefix(purl, "pkg:")) + "-" + hex.EncodeToString(sum[:4])
+}
+
+// graphDigest 返回依赖图内容的短哈希，用于生成稳定的文档命名空间
+func graphDigest(g *dependencyGraph) string {
+	h := sha256.New()
+	for _, ref := range g.order {
+		fmt.Fprintln(h, ref)
+		for _, child := range g.nodes[ref].dependsOn {
+			fmt.Fprintln(h, " ", child, g.nodes[ref].edges[child])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
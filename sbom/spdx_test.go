@@ -0,0 +1,185 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+func testSPDXOptions() SPDXOptions {
+	return SPDXOptions{
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Files: func(c model.Component) []ArtifactFile {
+			if c.CompName == "org.slf4j:slf4j-api" {
+				return []ArtifactFile{{
+					Name:      "slf4j-api-2.0.9.jar",
+					Checksums: map[string]string{"SHA1": "7CF2726FDCFBC8610F9A71FB3A14EF9B8A1F8E8B"},
+				}}
+			}
+			return nil
+		},
+	}
+}
+
+// fakeLocator 按组件名返回工件路径
+type fakeLocator map[string]string
+
+func (l fakeLocator) ComponentPath(c model.Component) string {
+	return l[c.CompName]
+}
+
+func TestLocalArtifactFiles(t *testing.T) {
+	dir := t.TempDir()
+	jar := filepath.Join(dir, "slf4j-api-2.0.9.jar")
+	if err := os.WriteFile(jar, []byte("jar"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jar+".sha256", []byte("ABCDEF  slf4j-api-2.0.9.jar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := LocalArtifactFiles(fakeLocator{
+		"org.slf4j:slf4j-api": jar,
+		"junit:junit":         filepath.Join(dir, "junit-4.13.2.jar"),
+	})
+
+	// 有校验文件时直接读取，没有时计算工件的 SHA1
+	want := []ArtifactFile{{
+		Name: "slf4j-api-2.0.9.jar",
+		Checksums: map[string]string{
+			"SHA1":   "f92e777f4341930bad9b2422283c4680d00dbc06",
+			"SHA256": "abcdef",
+		},
+	}}
+	if got := files(model.Component{CompName: "org.slf4j:slf4j-api"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("LocalArtifactFiles() = %v, want %v", got, want)
+	}
+	for _, name := range []string{"junit:junit", "com.example:unknown"} {
+		if got := files(model.Component{CompName: name}); got != nil {
+			t.Errorf("%s 的工件不存在时 LocalArtifactFiles() = %v, want nil", name, got)
+		}
+	}
+}
+
+func TestBuildSPDX(t *testing.T) {
+	doc := BuildSPDX(testModules(), testSPDXOptions())
+
+	if doc.SPDXVersion != "SPDX-2.3" || doc.Name != "com.example:core" {
+		t.Errorf("文档头 = %v %v", doc.SPDXVersion, doc.Name)
+	}
+	if len(doc.Packages) != 6 {
+		t.Fatalf("packages 数量 = %d, want 6", len(doc.Packages))
+	}
+
+	ids := map[string]SPDXPackage{}
+	for _, pkg := range doc.Packages {
+		ids[pkg.ExternalRefs[0].Locator] = pkg
+		if !strings.HasPrefix(pkg.SPDXID, "SPDXRef-Package-") || spdxIDInvalidChars.MatchString(strings.TrimPrefix(pkg.SPDXID, "SPDXRef-")) {
+			t.Errorf("SPDX ID %q 不合法", pkg.SPDXID)
+		}
+	}
+	app := ids["pkg:maven/com.example/app@1.0.0"].SPDXID
+	core := ids["pkg:maven/com.example/core@1.0.0"].SPDXID
+	junit := ids["pkg:maven/junit/junit@4.13.2"].SPDXID
+	epoll := ids["pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64"].SPDXID
+	if ids["pkg:maven/com.example/app@1.0.0"].PrimaryPackagePurpose != "APPLICATION" {
+		t.Errorf("模块的 PrimaryPackagePurpose 应为 APPLICATION")
+	}
+
+	relationships := map[SPDXRelationship]bool{}
+	for _, r := range doc.Relationships {
+		relationships[r] = true
+	}
+	for _, want := range []SPDXRelationship{
+		{Element: "SPDXRef-DOCUMENT", Type: SPDXRelationshipDescribes, RelatedElement: core},
+		{Element: "SPDXRef-DOCUMENT", Type: SPDXRelationshipDescribes, RelatedElement: app},
+		{Element: app, Type: SPDXRelationshipDependsOn, RelatedElement: core},
+		{Element: junit, Type: SPDXRelationshipTestDependencyOf, RelatedElement: core},
+		{Element: epoll, Type: SPDXRelationshipDevDependencyOf, RelatedElement: app},
+	} {
+		if !relationships[want] {
+			t.Errorf("缺少关系 %+v", want)
+		}
+	}
+
+	// 已知 SHA1 的 package 包含文件与校验码
+	slf4j := ids["pkg:maven/org.slf4j/slf4j-api@2.0.9"]
+	if !slf4j.FilesAnalyzed || slf4j.VerificationCode == nil || len(slf4j.HasFiles) != 1 {
+		t.Fatalf("slf4j-api package = %+v", slf4j)
+	}
+	if want := spdxVerificationCode([]string{"7cf2726fdcfbc8610f9a71fb3a14ef9b8a1f8e8b"}); slf4j.VerificationCode.Value != want {
+		t.Errorf("VerificationCode = %v, want %v", slf4j.VerificationCode.Value, want)
+	}
+	if len(doc.Files) != 1 || doc.Files[0].FileName != "./slf4j-api-2.0.9.jar" || doc.Files[0].Checksums[0].Value != "7cf2726fdcfbc8610f9a71fb3a14ef9b8a1f8e8b" {
+		t.Errorf("Files = %+v", doc.Files)
+	}
+	if !relationships[SPDXRelationship{Element: slf4j.SPDXID, Type: SPDXRelationshipContains, RelatedElement: slf4j.HasFiles[0]}] {
+		t.Errorf("缺少 CONTAINS 关系")
+	}
+	if ids["pkg:maven/junit/junit@4.13.2"].FilesAnalyzed {
+		t.Errorf("未知校验和的 package 不应分析文件")
+	}
+}
+
+func TestBuildSPDX_Deterministic(t *testing.T) {
+	var first, second bytes.Buffer
+	if err := WriteSPDXJSON(&first, testModules(), testSPDXOptions()); err != nil {
+		t.Fatalf("WriteSPDXJSON() error = %v", err)
+	}
+	if err := WriteSPDXJSON(&second, testModules(), testSPDXOptions()); err != nil {
+		t.Fatalf("WriteSPDXJSON() error = %v", err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("两次生成的文档不一致")
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(first.Bytes(), &doc); err != nil {
+		t.Fatalf("输出不是合法的 JSON: %v", err)
+	}
+	if !strings.HasPrefix(doc["documentNamespace"].(string), "https://spdx.org/spdxdocs/com.example-core-") {
+		t.Errorf("documentNamespace = %v", doc["documentNamespace"])
+	}
+
+	// 依赖发生变化时命名空间随之变化
+	modules := testModules()
+	modules[0].Dependencies = modules[0].Dependencies[:1]
+	if BuildSPDX(modules, testSPDXOptions()).DocumentNamespace == doc["documentNamespace"] {
+		t.Errorf("内容不同的文档应使用不同的命名空间")
+	}
+}
+
+func TestSPDXDocument_WriteTagValue(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSPDXTagValue(&buf, testModules()[:1], testSPDXOptions()); err != nil {
+		t.Fatalf("WriteSPDXTagValue() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"SPDXVersion: SPDX-2.3\n",
+		"DocumentName: com.example:core\n",
+		"Creator: Tool: pom_component_parsing\n",
+		"Created: 2024-01-02T03:04:05Z\n",
+		"PackageName: org.slf4j:slf4j-api\n",
+		"FilesAnalyzed: true\n",
+		"PackageChecksum: SHA1: 7cf2726fdcfbc8610f9a71fb3a14ef9b8a1f8e8b\n",
+		"ExternalRef: PACKAGE-MANAGER purl pkg:maven/org.slf4j/slf4j-api@2.0.9\n",
+		"FileName: ./slf4j-api-2.0.9.jar\n",
+		"Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-maven-com.example-core-1.0.0-",
+		" TEST_DEPENDENCY_OF SPDXRef-Package-maven-com.example-core-1.0.0-",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("tag-value 中缺少 %q\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "FileName:") < strings.Index(out, "PackageName: org.slf4j:slf4j-api") {
+		t.Errorf("文件应紧跟在所属的 package 之后")
+	}
+}
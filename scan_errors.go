@@ -0,0 +1,146 @@
+package pom_component_parsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrGraphParse 表示依赖图或依赖树结果文件无法解析
+	ErrGraphParse = errors.New("依赖图解析失败")
+	// ErrPartialResult 表示只有部分模块扫描成功
+	ErrPartialResult = errors.New("部分模块扫描失败")
+)
+
+// GraphParseError 表示某个模块的结果文件无法解析，errors.Is(err, ErrGraphParse) 成立
+type GraphParseError struct {
+	Path string // 结果文件的路径
+	Err  error  // 读取或解析文件的错误
+}
+
+// Error 实现 error 接口
+func (e *GraphParseError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrGraphParse, e.Err)
+}
+
+// Unwrap 返回读取或解析文件的错误
+func (e *GraphParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrGraphParse) 成立
+func (e *GraphParseError) Is(target error) bool {
+	return target == ErrGraphParse
+}
+
+// ModuleError 表示单个模块扫描失败的原因
+type ModuleError struct {
+	// Module 是模块 pom.xml 相对于项目根目录的路径；
+	// 错误来自 Maven 构建日志时，为日志中的模块名（artifactId），无法确定模块时为空
+	Module string
+	Err    error
+}
+
+// Error 实现 error 接口
+func (e ModuleError) Error() string {
+	if e.Module == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("模块 %s: %v", e.Module, e.Err)
+}
+
+// Unwrap 返回模块的错误
+func (e ModuleError) Unwrap() error {
+	return e.Err
+}
+
+// PartialResultError 表示只有部分模块扫描成功，成功的模块随该错误一起返回。
+// errors.Is(err, ErrPartialResult) 成立，errors.Is/As 同样可以匹配任一模块的错误
+type PartialResultError struct {
+	Modules []ModuleError // 扫描失败的模块
+}
+
+// Error 实现 error 接口
+func (e *PartialResultError) Error() string {
+	msgs := make([]string, 0, len(e.Modules))
+	for _, m := range e.Modules {
+		msgs = append(msgs, m.Error())
+	}
+	return fmt.Sprintf("%v: %s", ErrPartialResult, strings.Join(msgs, "; "))
+}
+
+// Unwrap 返回各模块的错误
+func (e *PartialResultError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Modules))
+	for _, m := range e.Modules {
+		errs = append(errs, m)
+	}
+	return errs
+}
+
+// Is 使 errors.Is(err, ErrPartialResult) 成立
+func (e *PartialResultError) Is(target error) bool {
+	return target == ErrPartialResult
+}
+
+// isPartialResult 判断 err 本身是否为 *PartialResultError。
+// 不使用 errors.Is，因为所有策略都失败时的 *ScanError 同样可能包装了某个策略的 *PartialResultError
+func isPartialResult(err error) bool {
+	_, ok := err.(*PartialResultError)
+	return ok
+}
+
+// partialResult 在 errs 不为空时返回 *PartialResultError，否则返回 nil
+func partialResult(errs []ModuleError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &PartialResultError{Modules: errs}
+}
+
+// StrategyError 表示某个扫描策略失败的原因
+type StrategyError struct {
+	Strategy ScanStrategy
+	Err      error
+}
+
+// Error 实现 error 接口
+func (e StrategyError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Strategy, e.Err)
+}
+
+// Unwrap 返回扫描策略的错误
+func (e StrategyError) Unwrap() error {
+	return e.Err
+}
+
+// ScanError 表示所有扫描策略都失败，按尝试顺序记录每个策略的错误。
+// errors.Is(err, ErrInspection) 成立，errors.Is/As 同样可以匹配任一策略的错误，
+// 如 ErrMvnNotFound、ErrMvnTimeout、*MvnBuildError
+type ScanError struct {
+	Strategies []StrategyError
+}
+
+// Error 实现 error 接口
+func (e *ScanError) Error() string {
+	msgs := make([]string, 0, len(e.Strategies))
+	for _, s := range e.Strategies {
+		msgs = append(msgs, s.Error())
+	}
+	return fmt.Sprintf("%v: %s", ErrInspection, strings.Join(msgs, "; "))
+}
+
+// Unwrap 返回各扫描策略的错误
+func (e *ScanError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Strategies))
+	for _, s := range e.Strategies {
+		errs = append(errs, s)
+	}
+	return errs
+}
+
+// Is 使 errors.Is(err, ErrInspection) 成立
+func (e *ScanError) Is(target error) bool {
+	return target == ErrInspection
+}
@@ -0,0 +1,194 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestScanErrors_IsAs(t *testing.T) {
+	exitErr := &exec.ExitError{}
+	buildErr := &MvnBuildError{ExitCode: 1, Err: exitErr}
+	parseErr := &GraphParseError{Path: "app/target/dependency-graph.json", Err: errors.New("unexpected EOF")}
+	scanErr := &ScanError{Strategies: []StrategyError{
+		{Strategy: ScanByDepgraphPlugin, Err: buildErr},
+		{Strategy: ScanByDependencyTree, Err: &ProcessTerminatedError{Cause: ErrMvnIdleTimeout}},
+		{Strategy: ScanByPomResolver, Err: ErrMvnNotFound},
+	}}
+	partialErr := &PartialResultError{Modules: []ModuleError{{Module: "app/pom.xml", Err: parseErr}}}
+
+	tests := []struct {
+		name   string
+		err    error
+		is     []error
+		isNot  []error
+		asPtrs []any
+	}{
+		{
+			name:   "构建失败",
+			err:    buildErr,
+			is:     []error{ErrBuildFailure},
+			isNot:  []error{ErrInspection, ErrMvnTimeout},
+			asPtrs: []any{new(*exec.ExitError)},
+		},
+		{
+			name:   "依赖图解析失败",
+			err:    parseErr,
+			is:     []error{ErrGraphParse},
+			isNot:  []error{ErrBuildFailure},
+			asPtrs: []any{new(*GraphParseError)},
+		},
+		{
+			name:   "所有策略失败",
+			err:    scanErr,
+			is:     []error{ErrInspection, ErrBuildFailure, ErrMvnTimeout, ErrMvnIdleTimeout, ErrMvnNotFound},
+			isNot:  []error{ErrPartialResult, ErrGraphParse},
+			asPtrs: []any{new(*MvnBuildError), new(*ProcessTerminatedError), new(*exec.ExitError)},
+		},
+		{
+			name:   "部分模块失败",
+			err:    partialErr,
+			is:     []error{ErrPartialResult, ErrGraphParse},
+			isNot:  []error{ErrInspection},
+			asPtrs: []any{new(*GraphParseError), new(ModuleError)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, target := range tt.is {
+				if !errors.Is(tt.err, target) {
+					t.Errorf("errors.Is(%v, %v) = false", tt.err, target)
+				}
+			}
+			for _, target := range tt.isNot {
+				if errors.Is(tt.err, target) {
+					t.Errorf("errors.Is(%v, %v) = true", tt.err, target)
+				}
+			}
+			for _, target := range tt.asPtrs {
+				if !errors.As(tt.err, target) {
+					t.Errorf("errors.As(%v, %T) = false", tt.err, target)
+				}
+			}
+		})
+	}
+}
+
+func TestMvnBuildError_moduleErrors(t *testing.T) {
+	appErr := Diagnostic{Severity: DiagnosticError, Kind: DiagnosticUnresolvableArtifact, Project: "app", Coordinate: "com.foo:bar:jar:1.2"}
+	webErr := Diagnostic{Severity: DiagnosticError, Kind: DiagnosticAuthentication, Project: "web", StatusCode: 401}
+	warning := Diagnostic{Severity: DiagnosticWarning, Kind: DiagnosticUnresolvableArtifact, Project: "app"}
+
+	tests := []struct {
+		name string
+		err  *MvnBuildError
+		want []ModuleError
+	}{
+		{
+			name: "按模块拆分",
+			err:  &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{appErr, warning, webErr}},
+			want: []ModuleError{
+				{Module: "app", Err: &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{appErr}}},
+				{Module: "web", Err: &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{webErr}}},
+			},
+		},
+		{
+			name: "没有错误诊断",
+			err:  &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{warning}},
+			want: []ModuleError{{Err: &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{warning}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.moduleErrors(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("moduleErrors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartialBuildResult(t *testing.T) {
+	deps := newDepsMap()
+	deps.put(Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"}, nil, "app/pom.xml")
+	buildErr := &MvnBuildError{ExitCode: 1, Diagnostics: []Diagnostic{{Severity: DiagnosticError, Kind: DiagnosticOther, Project: "web"}}}
+	parseErr := &PartialResultError{Modules: []ModuleError{{Module: "lib/pom.xml", Err: &GraphParseError{Err: errors.New("bad json")}}}}
+
+	got, err := partialBuildResult(buildErr, deps, parseErr)
+	var partial *PartialResultError
+	if got != deps || !errors.As(err, &partial) {
+		t.Fatalf("partialBuildResult() = %v, %v", got, err)
+	}
+	if len(partial.Modules) != 2 || partial.Modules[0].Module != "web" || partial.Modules[1].Module != "lib/pom.xml" {
+		t.Errorf("Modules = %v", partial.Modules)
+	}
+
+	// 没有收集到任何模块时返回构建错误
+	if got, err := partialBuildResult(buildErr, newDepsMap(), nil); got != nil || err != buildErr {
+		t.Errorf("partialBuildResult() = %v, %v, want nil, %v", got, err, buildErr)
+	}
+}
+
+func TestScanDeps_Partial(t *testing.T) {
+	deps := newDepsMap()
+	deps.put(Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"}, nil, "app/pom.xml")
+	complete := newDepsMap()
+	complete.put(Coordinate{GroupId: "com.example", ArtifactId: "parent", Version: "1.0.0"}, nil, "pom.xml")
+	complete.put(Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"}, nil, "app/pom.xml")
+	partialErr := &PartialResultError{Modules: []ModuleError{{Module: "pom.xml", Err: errors.New("parse failed")}}}
+	scanners := []Scanner{
+		fakeScanner{strategy: ScanByDepgraphPlugin, deps: deps, err: partialErr},
+		fakeScanner{strategy: ScanByPomResolver, deps: complete},
+	}
+
+	// 默认模式下部分结果视为失败，回退到下一个策略
+	got, strategy, err := ScanDeps(context.Background(), t.TempDir(), scanners)
+	if err != nil || strategy != ScanByPomResolver || got.Size() != 2 {
+		t.Errorf("ScanDeps() = %v, %v, %v", got.Size(), strategy, err)
+	}
+
+	// 部分结果模式下接受部分结果
+	got, strategy, err = scanDeps(context.Background(), zaptest.NewLogger(t), t.TempDir(), scanners, true)
+	if !errors.Is(err, ErrPartialResult) || strategy != ScanByDepgraphPlugin || got.Size() != 1 {
+		t.Errorf("scanDeps() = %v, %v, %v", got.Size(), strategy, err)
+	}
+}
+
+func TestScanMavenProjectWithOptions_AllowPartial(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>app</module>
+    <module>missing</module>
+  </modules>
+</project>`)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	opts := ScanOptions{Strategies: []ScanStrategy{ScanByPomResolver}}
+	if _, err := ScanMavenProjectWithOptions(context.Background(), dir, opts); !errors.Is(err, ErrInspection) {
+		t.Errorf("默认模式下 error = %v, want %v", err, ErrInspection)
+	}
+
+	opts.AllowPartial = true
+	modules, err := ScanMavenProjectWithOptions(context.Background(), dir, opts)
+	var partial *PartialResultError
+	if !errors.As(err, &partial) {
+		t.Fatalf("ScanMavenProjectWithOptions() error = %v, want *PartialResultError", err)
+	}
+	if len(partial.Modules) != 1 || partial.Modules[0].Module != filepath.Join("missing", "pom.xml") {
+		t.Errorf("Modules = %v", partial.Modules)
+	}
+	if len(modules) != 2 {
+		t.Errorf("ScanMavenProjectWithOptions() 返回 %d 个模块, want 2", len(modules))
+	}
+}
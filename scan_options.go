@@ -0,0 +1,181 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/liwenson/pom_component_parsing/model"
+	"go.uber.org/zap"
+)
+
+// ScanOptions 是 ScanMavenProjectWithOptions 的选项，零值与 ScanMavenProject 的行为一致
+type ScanOptions struct {
+	Timeout       time.Duration  // Maven 命令的超时时间，为 0 时使用 DefaultMvnTimeout，设置了 IdleTimeout 时使用 DefaultMvnIdleModeTimeout
+	Profiles      []string       // 显式激活的 profile，为空时按各模块与 settings.xml 中的激活条件评估
+	SettingsPath  string         // Maven settings.xml 的路径，为空时使用 Maven 的默认配置
+	JavaHome      string         // 运行 Maven 使用的 JAVA_HOME，为空时自动查找
+	MavenPath     string         // mvn 可执行文件的路径，为空时从 PATH 中查找
+	PluginVersion string         // depgraph-maven-plugin 的版本，为空时使用 DefaultDepgraphPluginVersion
+	Strategies    []ScanStrategy // 按顺序尝试的扫描策略，为空时依次使用 depgraph、dependency-tree、pom-resolver
+	Logger        *zap.Logger    // 日志记录器，为空时不输出日志
+	MvnCmdOptions                // 额外参数、离线模式、TLS 校验、空闲超时、Maven 输出的去向与扫描进度回调
+}
+
+// DefaultScanStrategies 是 ScanOptions.Strategies 为空时使用的扫描策略顺序
+var DefaultScanStrategies = []ScanStrategy{ScanByDepgraphPlugin, ScanByDependencyTree, ScanByPomResolver}
+
+// ScanMavenProjectWithOptions 按选项扫描指定目录下的 Maven 项目，返回模块列表或错误。
+// 只有选择了 depgraph 或 dependency-tree 策略时才检查 Maven 环境，Maven 不可用时这些策略会失败并回退到后续策略。
+// opts.AllowPartial 为 true 且只有部分模块扫描成功时，同时返回成功的模块与 *PartialResultError。
+func ScanMavenProjectWithOptions(ctx context.Context, dir string, opts ScanOptions) ([]model.Module, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	logger := loggerOrNop(opts.Logger)
+	start := time.Now()
+	logger.Info("开始扫描 Maven 项目", zap.String("dir", dir))
+
+	// 只有选择了需要 Maven 的策略时才检查 Maven 环境，纯 POM 解析不依赖 mvn 命令
+	var info *MvnCommandInfo
+	var err error
+	if opts.needsMaven() {
+		checkStart := time.Now()
+		info, err = resolveMvnCommand(ctx, logger, opts)
+		opts.Progress.emit(ProgressEvent{Phase: ProgressEnvironmentCheck, Duration: time.Since(checkStart), Err: err})
+		if err != nil {
+			logger.Warn("Maven 命令不可用", zap.Error(err))
+		}
+	}
+	scanners, err := opts.scanners(info, err)
+	if err != nil {
+		opts.Progress.emit(ProgressEvent{Phase: ProgressDone, Duration: time.Since(start), Err: err})
+		return nil, err
+	}
+
+	modules, err := scanMavenProject(ctx, logger, dir, scanners, opts.AllowPartial)
+	var strategy string
+	if len(modules) > 0 {
+		strategy = modules[0].ScanStrategy
+	}
+	opts.Progress.emit(ProgressEvent{Phase: ProgressDone, Duration: time.Since(start), Strategy: ScanStrategy(strategy), Total: len(modules), Err: err})
+	if err != nil && !isPartialResult(err) {
+		logger.Error("扫描 Maven 项目失败", zap.String("dir", dir), zap.Error(err))
+		return nil, err
+	}
+	if err != nil {
+		logger.Warn("部分模块扫描失败", zap.String("dir", dir), zap.Error(err))
+	}
+	logger.Info("扫描 Maven 项目完成",
+		zap.String("dir", dir),
+		zap.String("strategy", strategy),
+		zap.Int("modules", len(modules)),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return modules, err
+}
+
+// resolveMvnCommand 按选项确定 Maven 命令信息。未指定 Maven 路径与 JAVA_HOME 时使用 CheckMvnCommand 的缓存结果，
+// 否则使用指定的路径重新检查 Maven 版本
+func resolveMvnCommand(ctx context.Context, logger *zap.Logger, opts ScanOptions) (*MvnCommandInfo, error) {
+	if opts.MavenPath == "" && opts.JavaHome == "" {
+		cached, err := CheckMvnCommandWithLogger(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		// 复制一份，避免修改缓存的结果
+		info := *cached
+		if opts.SettingsPath != "" {
+			info.UserSettingsPath = opts.SettingsPath
+		}
+		return &info, nil
+	}
+
+	info := &MvnCommandInfo{Path: opts.MavenPath, JavaHome: opts.JavaHome, UserSettingsPath: opts.SettingsPath}
+	if info.Path == "" {
+		info.Path = getMvnCommandOs()
+		if info.Path == "" {
+			return nil, ErrMvnNotFound
+		}
+	}
+	if info.JavaHome == "" {
+		info.JavaHome = GetJavaHome()
+	}
+	ver, javaVer, err := checkMvnVersion(ctx, logger, info.Path, info.JavaHome)
+	if err != nil {
+		return nil, err
+	}
+	info.MvnVersion = ver
+	info.JavaVersion = javaVer
+	return info, nil
+}
+
+// strategies 返回按顺序尝试的扫描策略
+func (o ScanOptions) strategies() []ScanStrategy {
+	if len(o.Strategies) == 0 {
+		return DefaultScanStrategies
+	}
+	return o.Strategies
+}
+
+// needsMaven 判断扫描策略中是否有需要执行 Maven 命令的策略
+func (o ScanOptions) needsMaven() bool {
+	for _, strategy := range o.strategies() {
+		if strategy == ScanByDepgraphPlugin || strategy == ScanByDependencyTree {
+			return true
+		}
+	}
+	return false
+}
+
+// scanners 按选项构建扫描策略链，mvnErr 不为空时需要 Maven 的策略直接返回该错误
+func (o ScanOptions) scanners(info *MvnCommandInfo, mvnErr error) ([]Scanner, error) {
+	var scanners []Scanner
+	for _, strategy := range o.strategies() {
+		// 进度事件中记录产生事件的策略
+		progress := o.Progress.withStrategy(strategy)
+		mvnOpts := MvnScanOptions{
+			MavenCmdInfo:  info,
+			Profiles:      o.Profiles,
+			Timeout:       o.Timeout,
+			Logger:        o.Logger,
+			MvnCmdOptions: o.MvnCmdOptions,
+		}
+		mvnOpts.Progress = progress
+
+		var s Scanner
+		switch strategy {
+		case ScanByDepgraphPlugin:
+			s = DepgraphScanner{MvnScanOptions: mvnOpts, PluginVersion: o.PluginVersion}
+		case ScanByDependencyTree:
+			s = DependencyTreeScanner{MvnScanOptions: mvnOpts}
+		case ScanByPomResolver:
+			s = PomResolverScanner{SettingsPath: o.SettingsPath, Profiles: o.Profiles, Logger: o.Logger, Progress: progress}
+		case ScanByGraphFile:
+			s = GraphFileScanner{Logger: o.Logger, Progress: progress}
+		default:
+			return nil, fmt.Errorf("不支持的扫描策略: %s", strategy)
+		}
+		if mvnErr != nil && (strategy == ScanByDepgraphPlugin || strategy == ScanByDependencyTree) {
+			s = failedScanner{strategy: strategy, err: mvnErr}
+		}
+		scanners = append(scanners, s)
+	}
+	return scanners, nil
+}
+
+// failedScanner 是无法执行的扫描策略，Scan 总是返回 err
+type failedScanner struct {
+	strategy ScanStrategy
+	err      error
+}
+
+// Strategy 实现 Scanner 接口
+func (s failedScanner) Strategy() ScanStrategy {
+	return s.strategy
+}
+
+// Scan 实现 Scanner 接口
+func (s failedScanner) Scan(context.Context, string) (*DepsMap, error) {
+	return nil, s.err
+}
@@ -0,0 +1,149 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMvnCmdOptions_args(t *testing.T) {
+	goal := []string{"dependency:tree"}
+	tests := []struct {
+		name     string
+		opts     MvnCmdOptions
+		profiles []string
+		want     []string
+	}{
+		{
+			name: "默认选项",
+			want: append([]string{"dependency:tree"}, insecureTransportArgs...),
+		},
+		{
+			name:     "校验TLS、离线模式、profile与额外参数",
+			opts:     MvnCmdOptions{SecureTransport: true, Offline: true, ExtraArgs: []string{"-U", "-Dfoo=bar"}},
+			profiles: []string{"jdk17", "prod"},
+			want:     []string{"dependency:tree", "--offline", "-P", "jdk17,prod", "-U", "-Dfoo=bar"},
+		},
+		{
+			name: "部分结果模式",
+			opts: MvnCmdOptions{SecureTransport: true, AllowPartial: true},
+			want: []string{"dependency:tree", "--fail-at-end"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.args(goal, tt.profiles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("args() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOptions_scanners(t *testing.T) {
+	info := &MvnCommandInfo{Path: "/usr/bin/mvn"}
+	errMvn := errors.New("mvn not found")
+
+	tests := []struct {
+		name    string
+		opts    ScanOptions
+		mvnErr  error
+		want    []Scanner
+		wantErr bool
+	}{
+		{
+			name: "默认策略",
+			opts: ScanOptions{Profiles: []string{"prod"}, SettingsPath: "/etc/maven/settings.xml", PluginVersion: "4.0.3"},
+			want: []Scanner{
+				DepgraphScanner{MvnScanOptions: MvnScanOptions{MavenCmdInfo: info, Profiles: []string{"prod"}}, PluginVersion: "4.0.3"},
+				DependencyTreeScanner{MvnScanOptions: MvnScanOptions{MavenCmdInfo: info, Profiles: []string{"prod"}}},
+				PomResolverScanner{SettingsPath: "/etc/maven/settings.xml", Profiles: []string{"prod"}},
+			},
+		},
+		{
+			name:   "Maven不可用",
+			opts:   ScanOptions{Strategies: []ScanStrategy{ScanByDependencyTree, ScanByGraphFile}},
+			mvnErr: errMvn,
+			want: []Scanner{
+				failedScanner{strategy: ScanByDependencyTree, err: errMvn},
+				GraphFileScanner{},
+			},
+		},
+		{
+			name:    "不支持的策略",
+			opts:    ScanOptions{Strategies: []ScanStrategy{"gradle"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mvnInfo := info
+			if tt.mvnErr != nil {
+				mvnInfo = nil
+			}
+			got, err := tt.opts.scanners(mvnInfo, tt.mvnErr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scanners() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanners() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveMvnCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("使用 shell 脚本模拟 mvn")
+	}
+	dir := t.TempDir()
+	mvn := writeTestFile(t, dir, "mvn", "#!/bin/sh\necho 'Apache Maven 3.9.6 (bc0240f3c744dd6b6ec2920b3cd08dcc295161ae)'\necho 'Java version: 17.0.2, vendor: Oracle Corporation'\n")
+	if err := os.Chmod(mvn, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := resolveMvnCommand(context.Background(), zaptest.NewLogger(t), ScanOptions{MavenPath: mvn, JavaHome: dir, SettingsPath: "/etc/maven/settings.xml"})
+	if err != nil {
+		t.Fatalf("resolveMvnCommand() error = %v", err)
+	}
+	want := &MvnCommandInfo{Path: mvn, MvnVersion: "3.9.6", UserSettingsPath: "/etc/maven/settings.xml", JavaHome: dir, JavaVersion: "17.0.2"}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("resolveMvnCommand() = %v, want %v", info, want)
+	}
+}
+
+func TestScanMavenProjectWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	// 只使用 POM 解析器时不检查 Maven 环境，指定的 mvn 不存在也不影响扫描
+	var phases []ProgressPhase
+	opts := ScanOptions{Strategies: []ScanStrategy{ScanByPomResolver}, MavenPath: filepath.Join(dir, "missing-mvn")}
+	opts.Progress = func(e ProgressEvent) { phases = append(phases, e.Phase) }
+	modules, err := ScanMavenProjectWithOptions(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("ScanMavenProjectWithOptions() error = %v", err)
+	}
+	if len(modules) == 0 || modules[0].ScanStrategy != string(ScanByPomResolver) {
+		t.Errorf("ScanMavenProjectWithOptions() = %v", modules)
+	}
+	for _, phase := range phases {
+		if phase == ProgressEnvironmentCheck {
+			t.Errorf("没有需要 Maven 的策略时不应检查 Maven 环境")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ScanMavenProjectWithOptions(ctx, dir, ScanOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("已取消的 context 应返回 %v, got %v", context.Canceled, err)
+	}
+}
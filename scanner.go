@@ -0,0 +1,229 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ScanStrategy 表示产生依赖扫描结果的策略，记录在 model.Module.ScanStrategy 中
+type ScanStrategy string
+
+const (
+	ScanByDepgraphPlugin ScanStrategy = "depgraph"        // com.github.ferstl:depgraph-maven-plugin:graph
+	ScanByDependencyTree ScanStrategy = "dependency-tree" // maven-dependency-plugin:tree
+	ScanByPomResolver    ScanStrategy = "pom-resolver"    // 纯 Go 的 POM 解析器
+	ScanByGraphFile      ScanStrategy = "graph-file"      // 项目中已经生成好的依赖图文件
+)
+
+// Scanner 是依赖扫描策略的接口
+type Scanner interface {
+	// Strategy 返回扫描策略的名称
+	Strategy() ScanStrategy
+	// Scan 扫描 projectDir 下的项目，返回各模块的依赖关系，ctx 取消时应尽快返回 ctx 的错误
+	Scan(ctx context.Context, projectDir string) (*DepsMap, error)
+}
+
+// DefaultScanners 返回默认的扫描策略链：优先使用 depgraph 插件，
+// 插件无法下载时回退到 dependency:tree，Maven 不可用时回退到纯 Go 的 POM 解析
+func DefaultScanners() []Scanner {
+	return []Scanner{
+		DepgraphScanner{},
+		DependencyTreeScanner{},
+		PomResolverScanner{},
+	}
+}
+
+// ScanDeps 依次使用 scanners 扫描依赖，返回第一个得到结果的策略及其结果。
+// 某个策略执行失败（包括只有部分模块成功）或没有结果时回退到下一个，全部失败时返回记录了每个策略错误的 *ScanError；
+// ctx 取消时不再回退，直接返回 ctx 的错误
+func ScanDeps(ctx context.Context, projectDir string, scanners []Scanner) (*DepsMap, ScanStrategy, error) {
+	return scanDeps(ctx, zap.NewNop(), projectDir, scanners, false)
+}
+
+// scanDeps 与 ScanDeps 相同，allowPartial 为 true 时接受只有部分模块成功的策略，
+// 返回成功模块的依赖关系与该策略的 *PartialResultError
+func scanDeps(ctx context.Context, logger *zap.Logger, projectDir string, scanners []Scanner, allowPartial bool) (*DepsMap, ScanStrategy, error) {
+	var errs []StrategyError
+	for _, s := range scanners {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		start := time.Now()
+		deps, err := s.Scan(ctx, projectDir)
+		fields := []zap.Field{zap.String("strategy", string(s.Strategy())), zap.Duration("duration", time.Since(start))}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+		if err != nil {
+			var partial *PartialResultError
+			if allowPartial && errors.As(err, &partial) && deps != nil && deps.Size() > 0 {
+				logger.Warn("扫描依赖得到部分结果", append(fields, zap.Int("modules", deps.Size()), zap.Error(err))...)
+				return deps, s.Strategy(), partial
+			}
+			logger.Warn("扫描依赖时出错，回退到下一个策略", append(fields, zap.Error(err))...)
+			errs = append(errs, StrategyError{Strategy: s.Strategy(), Err: err})
+			continue
+		}
+		if deps == nil || deps.Size() == 0 {
+			logger.Info("扫描依赖没有结果，回退到下一个策略", fields...)
+			continue
+		}
+		logger.Info("扫描依赖成功", append(fields, zap.Int("modules", deps.Size()))...)
+		return deps, s.Strategy(), nil
+	}
+	if len(errs) > 0 {
+		return nil, "", &ScanError{Strategies: errs}
+	}
+	return newDepsMap(), "", nil
+}
+
+// mvnCommand 返回 info，info 为空时检查系统中的 Maven 命令
+func mvnCommand(ctx context.Context, logger *zap.Logger, info *MvnCommandInfo) (*MvnCommandInfo, error) {
+	if info != nil {
+		return info, nil
+	}
+	return CheckMvnCommandWithLogger(ctx, logger)
+}
+
+// MvnScanOptions 是执行 Maven 命令的扫描策略共用的选项
+type MvnScanOptions struct {
+	MavenCmdInfo  *MvnCommandInfo // Maven 命令信息，为空时自动检查系统中的 Maven 命令
+	Profiles      []string        // 显式激活的 profile，为空时按激活条件评估
+	Timeout       time.Duration   // Maven 命令的超时时间，为 0 时使用 DefaultMvnTimeout，设置了 IdleTimeout 时使用 DefaultMvnIdleModeTimeout
+	Logger        *zap.Logger     // 日志记录器，为空时不输出日志
+	MvnCmdOptions                 // 通用的 Maven 命令选项
+}
+
+// prepare 返回 Maven 命令信息、需要通过 -P 激活的 profile 与超时时间。
+// 没有显式指定 profile 时只评估并报告处于激活状态的 profile，不传递 -P，由 Maven 在每个模块中分别评估
+func (o MvnScanOptions) prepare(ctx context.Context, projectDir string) (*MvnCommandInfo, []string, time.Duration, error) {
+	start := time.Now()
+	info, err := mvnCommand(ctx, loggerOrNop(o.Logger), o.MavenCmdInfo)
+	if o.MavenCmdInfo == nil {
+		o.Progress.emit(ProgressEvent{Phase: ProgressEnvironmentCheck, Duration: time.Since(start), Err: err})
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(o.Profiles) == 0 {
+		start = time.Now()
+		active := activeProfiles(loggerOrNop(o.Logger), projectDir, info)
+		o.Progress.emit(ProgressEvent{Phase: ProgressProfileDiscovery, Duration: time.Since(start), Profiles: active})
+	}
+	timeout := o.Timeout
+	if timeout == 0 {
+		timeout = DefaultMvnTimeout
+		if o.IdleTimeout > 0 {
+			timeout = DefaultMvnIdleModeTimeout
+		}
+	}
+	return info, o.Profiles, timeout, nil
+}
+
+// DepgraphScanner 使用 depgraph-maven-plugin 扫描依赖
+type DepgraphScanner struct {
+	MvnScanOptions
+	PluginVersion string // depgraph-maven-plugin 的版本，为空时使用 DefaultDepgraphPluginVersion
+}
+
+// Strategy 实现 Scanner 接口
+func (s DepgraphScanner) Strategy() ScanStrategy {
+	return ScanByDepgraphPlugin
+}
+
+// Scan 实现 Scanner 接口
+func (s DepgraphScanner) Scan(ctx context.Context, projectDir string) (*DepsMap, error) {
+	info, profiles, timeout, err := s.prepare(ctx, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return scanDepsByPluginGraphCmd(ctx, PluginGraphCmd{
+		Profiles:      profiles,
+		Timeout:       timeout,
+		ScanDir:       projectDir,
+		PluginVersion: s.PluginVersion,
+		MavenCmdInfo:  info,
+		Logger:        s.Logger,
+		MvnCmdOptions: s.MvnCmdOptions,
+	})
+}
+
+// DependencyTreeScanner 使用 maven-dependency-plugin:tree 扫描依赖
+type DependencyTreeScanner struct {
+	MvnScanOptions
+	OutputType DependencyTreeOutputType // dependency:tree 的输出格式，为空时使用 text
+}
+
+// Strategy 实现 Scanner 接口
+func (s DependencyTreeScanner) Strategy() ScanStrategy {
+	return ScanByDependencyTree
+}
+
+// Scan 实现 Scanner 接口
+func (s DependencyTreeScanner) Scan(ctx context.Context, projectDir string) (*DepsMap, error) {
+	info, profiles, timeout, err := s.prepare(ctx, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return scanDepsByDependencyTreeCmd(ctx, DependencyTreeCmd{
+		Profiles:      profiles,
+		Timeout:       timeout,
+		ScanDir:       projectDir,
+		OutputType:    s.OutputType,
+		MavenCmdInfo:  info,
+		Logger:        s.Logger,
+		MvnCmdOptions: s.MvnCmdOptions,
+	})
+}
+
+// PomResolverScanner 使用纯 Go 的 POM 解析器扫描依赖，不需要 Maven 与 Java 环境
+type PomResolverScanner struct {
+	SettingsPath string       // 读取本地仓库位置的 settings.xml 路径，为空时使用 Maven 的默认配置
+	Profiles     []string     // 显式激活的 profile，相当于 mvn -P，为空时按各模块的激活条件评估
+	Logger       *zap.Logger  // 日志记录器，为空时不输出日志
+	Progress     ProgressFunc // 不为空时接收每个模块解析完成的进度事件
+}
+
+// Strategy 实现 Scanner 接口
+func (s PomResolverScanner) Strategy() ScanStrategy {
+	return ScanByPomResolver
+}
+
+// Scan 实现 Scanner 接口
+func (s PomResolverScanner) Scan(ctx context.Context, projectDir string) (*DepsMap, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return scanDepsByPomResolver(newScanPomResolver(s.SettingsPath, s.Profiles), loggerOrNop(s.Logger), s.Progress, projectDir)
+}
+
+// GraphFileScanner 读取项目中已经生成好的依赖图文件，不执行 Maven 命令。
+// 优先读取 dependency-graph.json，没有时依次读取 text、tgf、dot 格式的 dependency:tree 结果文件
+type GraphFileScanner struct {
+	Logger   *zap.Logger  // 日志记录器，为空时不输出日志
+	Progress ProgressFunc // 不为空时接收每个结果文件解析完成的进度事件
+}
+
+// Strategy 实现 Scanner 接口
+func (s GraphFileScanner) Strategy() ScanStrategy {
+	return ScanByGraphFile
+}
+
+// Scan 实现 Scanner 接口
+func (s GraphFileScanner) Scan(ctx context.Context, projectDir string) (*DepsMap, error) {
+	logger := loggerOrNop(s.Logger)
+	deps, err := collectPluginResultFile(ctx, logger, s.Progress, projectDir)
+	if err != nil || deps.Size() > 0 {
+		return deps, err
+	}
+	for _, outputType := range []DependencyTreeOutputType{DependencyTreeText, DependencyTreeTGF, DependencyTreeDOT} {
+		deps, err = collectDependencyTreeFiles(ctx, logger, s.Progress, projectDir, outputType)
+		if err != nil || deps.Size() > 0 {
+			return deps, err
+		}
+	}
+	return deps, nil
+}
@@ -0,0 +1,206 @@
+package pom_component_parsing
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeScanner 是用于测试策略链的扫描策略
+type fakeScanner struct {
+	strategy ScanStrategy
+	deps     *DepsMap
+	err      error
+	called   *int
+}
+
+func (s fakeScanner) Strategy() ScanStrategy {
+	return s.strategy
+}
+
+func (s fakeScanner) Scan(context.Context, string) (*DepsMap, error) {
+	if s.called != nil {
+		*s.called++
+	}
+	return s.deps, s.err
+}
+
+func TestScanDeps(t *testing.T) {
+	deps := newDepsMap()
+	deps.put(Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0"}, nil, "pom.xml")
+	errScan := errors.New("scan failed")
+
+	tests := []struct {
+		name         string
+		scanners     []Scanner
+		wantStrategy ScanStrategy
+		wantSize     int
+		wantErr      error
+		wantCalls    int
+	}{
+		{
+			name:         "第一个策略成功",
+			scanners:     []Scanner{fakeScanner{strategy: ScanByDepgraphPlugin, deps: deps}, fakeScanner{strategy: ScanByPomResolver}},
+			wantStrategy: ScanByDepgraphPlugin,
+			wantSize:     1,
+		},
+		{
+			name: "失败或没有结果时回退",
+			scanners: []Scanner{
+				fakeScanner{strategy: ScanByDepgraphPlugin, err: errScan},
+				fakeScanner{strategy: ScanByDependencyTree, deps: newDepsMap()},
+				fakeScanner{strategy: ScanByPomResolver, deps: deps},
+			},
+			wantStrategy: ScanByPomResolver,
+			wantSize:     1,
+		},
+		{
+			name:     "全部失败返回最后一个错误",
+			scanners: []Scanner{fakeScanner{strategy: ScanByDepgraphPlugin, err: errScan}, fakeScanner{strategy: ScanByPomResolver}},
+			wantErr:  errScan,
+		},
+		{
+			name:     "全部没有结果",
+			scanners: []Scanner{fakeScanner{strategy: ScanByGraphFile, deps: newDepsMap()}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, strategy, err := ScanDeps(context.Background(), t.TempDir(), tt.scanners)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScanDeps() error = %v, want %v", err, tt.wantErr)
+			}
+			if strategy != tt.wantStrategy {
+				t.Errorf("ScanDeps() strategy = %v, want %v", strategy, tt.wantStrategy)
+			}
+			if err == nil && got.Size() != tt.wantSize {
+				t.Errorf("ScanDeps() size = %d, want %d", got.Size(), tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestScanMavenProjectWithScanners(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	called := 0
+	modules, err := ScanMavenProjectWithScanners(context.Background(), dir,
+		fakeScanner{strategy: ScanByDepgraphPlugin, err: errors.New("plugin blocked"), called: &called},
+		PomResolverScanner{},
+	)
+	if err != nil {
+		t.Fatalf("ScanMavenProjectWithScanners() error = %v", err)
+	}
+	if called != 1 || len(modules) == 0 {
+		t.Fatalf("called = %d, modules = %v", called, modules)
+	}
+	for _, m := range modules {
+		if m.ScanStrategy != string(ScanByPomResolver) {
+			t.Errorf("模块 %v 的 ScanStrategy = %q, want %q", m, m.ScanStrategy, ScanByPomResolver)
+		}
+	}
+
+	if _, err := ScanMavenProjectWithScanners(context.Background(), dir, fakeScanner{strategy: ScanByDepgraphPlugin, err: errors.New("plugin blocked")}); !errors.Is(err, ErrInspection) {
+		t.Errorf("所有策略失败时 error = %v, want %v", err, ErrInspection)
+	}
+}
+
+func TestGraphFileScanner(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "app/target/dependency-tree.tgf", "1 com.example:app:jar:1.0.0\n2 org.slf4j:slf4j-api:jar:2.0.9:compile\n#\n1 2 compile\n")
+
+	deps, strategy, err := ScanDeps(context.Background(), dir, []Scanner{GraphFileScanner{}})
+	if err != nil || strategy != ScanByGraphFile {
+		t.Fatalf("ScanDeps() = %v, %v", strategy, err)
+	}
+	entries := deps.ListAllEntries()
+	if len(entries) != 1 || entries[0].relativePath != filepath.Join("app", "pom.xml") || len(entries[0].children) != 1 {
+		t.Errorf("ListAllEntries() = %+v", entries)
+	}
+}
+
+func TestPomResolverScanner_SettingsAndProfiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", "")
+
+	// 父 POM 只存在于 settings.xml 指定的本地仓库中
+	repo := NewLocalRepository(filepath.Join(dir, "custom-repo"))
+	parentPath := repo.PomPath(Coordinate{GroupId: "com.corp", ArtifactId: "corp-parent", Version: "3"})
+	writeTestFile(t, filepath.Dir(parentPath), filepath.Base(parentPath), `<project>
+  <groupId>com.corp</groupId>
+  <artifactId>corp-parent</artifactId>
+  <version>3</version>
+</project>`)
+	settings := writeTestFile(t, dir, "settings.xml", `<settings>
+  <localRepository>${user.home}/custom-repo</localRepository>
+</settings>`)
+
+	project := filepath.Join(dir, "project")
+	writeTestFile(t, project, "pom.xml", `<project>
+  <parent>
+    <groupId>com.corp</groupId>
+    <artifactId>corp-parent</artifactId>
+    <version>3</version>
+  </parent>
+  <artifactId>service</artifactId>
+  <profiles>
+    <profile>
+      <id>prod</id>
+      <dependencies>
+        <dependency>
+          <groupId>org.slf4j</groupId>
+          <artifactId>slf4j-api</artifactId>
+          <version>2.0.9</version>
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>`)
+
+	deps, err := PomResolverScanner{SettingsPath: settings, Profiles: []string{"prod"}}.Scan(context.Background(), project)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	entries := deps.ListAllEntries()
+	want := Coordinate{GroupId: "com.corp", ArtifactId: "service", Version: "3"}
+	if len(entries) != 1 || entries[0].coordinate != want {
+		t.Fatalf("ListAllEntries() = %+v, want %v", entries, want)
+	}
+	if children := entries[0].children; len(children) != 1 || children[0].ArtifactId != "slf4j-api" {
+		t.Errorf("显式激活的 profile 中的依赖 = %v", children)
+	}
+}
+
+func TestScanMavenProjectWithOptions_Logger(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "pom.xml", testParentPom)
+	writeTestFile(t, dir, "app/pom.xml", testAppPom)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	opts := ScanOptions{Strategies: []ScanStrategy{ScanByPomResolver}, Logger: zap.New(core)}
+	if _, err := ScanMavenProjectWithOptions(context.Background(), dir, opts); err != nil {
+		t.Fatalf("ScanMavenProjectWithOptions() error = %v", err)
+	}
+
+	entries := logs.FilterMessage("扫描依赖成功").All()
+	if len(entries) != 1 {
+		t.Fatalf("日志 = %v, want 1 条扫描成功的日志", logs.All())
+	}
+	fields := entries[0].ContextMap()
+	if fields["strategy"] != string(ScanByPomResolver) || fields["modules"] != int64(2) {
+		t.Errorf("日志字段 = %v", fields)
+	}
+	if _, ok := fields["duration"]; !ok {
+		t.Errorf("日志字段 = %v, 缺少 duration", fields)
+	}
+}
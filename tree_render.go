@@ -0,0 +1,97 @@
+package pom_component_parsing
+
+import (
+	"io"
+	"strings"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+// TreeOptions 控制 dependency:tree 风格文本的输出
+type TreeOptions struct {
+	Verbose bool // 与 mvn dependency:tree -Dverbose 一致，输出因重复或版本冲突而被省略的依赖
+}
+
+// RenderTree 以 mvn dependency:tree 的格式输出以 root 为根的依赖树。
+// 与 Maven 一致，同一工件只在最近（深度相同时取最先声明）的位置展开，其余位置被省略
+func RenderTree(w io.Writer, root Dependency, opts TreeOptions) error {
+	winners := nearestDependencies(&root)
+
+	var sb strings.Builder
+	sb.WriteString(treeCoordinate(root.Coordinate, ""))
+	sb.WriteString("\n")
+	writeTreeChildren(&sb, &root, "", winners, opts)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// RenderModuleTree 以 mvn dependency:tree 的格式输出模块的依赖树
+func RenderModuleTree(w io.Writer, m model.Module, opts TreeOptions) error {
+	return RenderTree(w, ModuleDependencyTree(m), opts)
+}
+
+// nearestDependencies 按广度优先遍历依赖树，返回每个工件（键为 Coordinate.ManagementKey）最终生效的节点
+func nearestDependencies(root *Dependency) map[string]*Dependency {
+	winners := map[string]*Dependency{root.ManagementKey(): root}
+	queue := []*Dependency{root}
+	for len(queue) > 0 {
+		d := queue[0]
+		queue = queue[1:]
+		for i := range d.Children {
+			child := &d.Children[i]
+			if _, ok := winners[child.ManagementKey()]; ok {
+				continue
+			}
+			winners[child.ManagementKey()] = child
+			queue = append(queue, child)
+		}
+	}
+	return winners
+}
+
+// writeTreeChildren 递归输出 d 的子依赖，prefix 为当前层级的缩进
+func writeTreeChildren(sb *strings.Builder, d *Dependency, prefix string, winners map[string]*Dependency, opts TreeOptions) {
+	var children []*Dependency
+	for i := range d.Children {
+		child := &d.Children[i]
+		if opts.Verbose || winners[child.ManagementKey()] == child {
+			children = append(children, child)
+		}
+	}
+
+	for i, child := range children {
+		branch, indent := "+- ", "|  "
+		if i == len(children)-1 {
+			branch, indent = "\\- ", "   "
+		}
+		sb.WriteString(prefix + branch)
+
+		winner := winners[child.ManagementKey()]
+		if winner != child {
+			sb.WriteString("(" + treeCoordinate(child.Coordinate, child.Scope) + " - ")
+			if winner.Version == child.Version {
+				sb.WriteString("omitted for duplicate)\n")
+			} else {
+				sb.WriteString("omitted for conflict with " + winner.Version + ")\n")
+			}
+			continue
+		}
+		sb.WriteString(treeCoordinate(child.Coordinate, child.Scope))
+		sb.WriteString("\n")
+		writeTreeChildren(sb, child, prefix+indent, winners, opts)
+	}
+}
+
+// treeCoordinate 返回 Maven 输出工件时使用的格式 groupId:artifactId:type[:classifier]:version[:scope]
+func treeCoordinate(c Coordinate, scope string) string {
+	parts := []string{c.GroupId, c.ArtifactId, c.ArtifactType()}
+	if c.Classifier != "" {
+		parts = append(parts, c.Classifier)
+	}
+	parts = append(parts, c.Version)
+	if scope != "" {
+		parts = append(parts, scope)
+	}
+	return strings.Join(parts, ":")
+}
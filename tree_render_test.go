@@ -0,0 +1,101 @@
+package pom_component_parsing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/liwenson/pom_component_parsing/model"
+)
+
+func TestRenderTree(t *testing.T) {
+	// app -> core -> slf4j 1.7.30，app -> slf4j 1.7.36，app -> epoll(linux-x86_64, provided)
+	conflict := Dependency{
+		Coordinate: Coordinate{GroupId: "com.example", ArtifactId: "app", Version: "1.0.0", Type: "war"},
+		Children: []Dependency{
+			{
+				Coordinate: Coordinate{GroupId: "com.example", ArtifactId: "core", Version: "1.0.0"},
+				Scope:      "compile",
+				Children: []Dependency{
+					{Coordinate: Coordinate{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "1.7.30"}, Scope: "compile"},
+				},
+			},
+			{Coordinate: Coordinate{GroupId: "org.slf4j", ArtifactId: "slf4j-api", Version: "1.7.36"}, Scope: "compile"},
+			{Coordinate: Coordinate{GroupId: "io.netty", ArtifactId: "netty-transport-native-epoll", Version: "4.1.100.Final", Classifier: "linux-x86_64"}, Scope: "provided"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		root Dependency
+		opts TreeOptions
+		want string
+	}{
+		{
+			name: "省略重复的依赖",
+			root: testDependencyTree(),
+			want: `com.example:app:jar:1.0.0
++- org.springframework:spring-web:jar:5.3.30:compile
++- junit:junit:jar:4.13.2:test
+|  \- org.hamcrest:hamcrest-core:jar:1.3:test
+\- org.springframework:spring-core:jar:5.3.30:compile
+   \- commons-logging:commons-logging:jar:1.2:compile
+`,
+		},
+		{
+			name: "verbose输出重复的依赖",
+			root: testDependencyTree(),
+			opts: TreeOptions{Verbose: true},
+			want: `com.example:app:jar:1.0.0
++- org.springframework:spring-web:jar:5.3.30:compile
+|  \- (org.springframework:spring-core:jar:5.3.30:compile - omitted for duplicate)
++- junit:junit:jar:4.13.2:test
+|  \- org.hamcrest:hamcrest-core:jar:1.3:test
+\- org.springframework:spring-core:jar:5.3.30:compile
+   \- commons-logging:commons-logging:jar:1.2:compile
+`,
+		},
+		{
+			name: "verbose输出版本冲突",
+			root: conflict,
+			opts: TreeOptions{Verbose: true},
+			want: `com.example:app:war:1.0.0
++- com.example:core:jar:1.0.0:compile
+|  \- (org.slf4j:slf4j-api:jar:1.7.30:compile - omitted for conflict with 1.7.36)
++- org.slf4j:slf4j-api:jar:1.7.36:compile
+\- io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:provided
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RenderTree(&buf, tt.root, tt.opts); err != nil {
+				t.Fatalf("RenderTree() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("RenderTree() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderModuleTree(t *testing.T) {
+	tree := testDependencyTree()
+	m := model.Module{
+		ModuleName:    tree.Coordinate.Name(),
+		ModuleVersion: tree.Coordinate.Version,
+		Dependencies:  convDeps(tree.Children),
+	}
+
+	var want, got bytes.Buffer
+	if err := RenderTree(&want, tree, TreeOptions{}); err != nil {
+		t.Fatalf("RenderTree() error = %v", err)
+	}
+	if err := RenderModuleTree(&got, m, TreeOptions{}); err != nil {
+		t.Fatalf("RenderModuleTree() error = %v", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("RenderModuleTree() =\n%s\nwant\n%s", got.String(), want.String())
+	}
+}
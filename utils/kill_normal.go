@@ -3,9 +3,15 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 )
 
 // KillProcessGroup 终止指定进程ID对应的整个进程组
@@ -45,28 +51,99 @@ func SendSignalToGroup(pid int, sig syscall.Signal) error {
 }
 
 // GracefullyKillGroup 尝试优雅地终止进程组
-// 首先发送SIGTERM，等待一段时间后如果进程仍然存在，则发送SIGKILL
+// 首先发送SIGTERM，等待一段时间后如果进程组中仍有进程存在，则发送SIGKILL
 func GracefullyKillGroup(pid int, timeout int) error {
-	// 首先发送SIGTERM信号
+	// 首先发送SIGTERM信号，进程组已经不存在时视为成功
 	if err := SendSignalToGroup(pid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
 		return fmt.Errorf("failed to send SIGTERM to process group %d: %w", pid, err)
 	}
 
-	// 检查进程是否存在
+	// 检查进程组中是否还有进程存在，组长进程退出后其子进程仍可能存活
 	exists := func(pid int) bool {
-		return syscall.Kill(pid, 0) == nil
+		return syscall.Kill(-pid, 0) == nil
 	}
 
-	// 等待进程退出或超时
+	// 等待进程组退出或超时
 	for i := 0; i < timeout; i++ {
 		if !exists(pid) {
 			return nil
 		}
-		syscall.Sleep(time.Second)
+		time.Sleep(time.Second)
+	}
+
+	// 如果进程组中仍有进程存在，发送SIGKILL信号
+	if err := KillProcessGroup(pid); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
+
+// ListProcessGroup 列出进程组 pgid 中的所有进程
+// 优先读取 /proc，不可用时（如 macOS）使用 ps 命令
+func ListProcessGroup(pgid int) ([]ProcessInfo, error) {
+	if rs, err := listProcessGroupProc(pgid); err == nil {
+		return rs, nil
+	}
+	return listProcessGroupPs(pgid)
+}
+
+// listProcessGroupProc 通过 /proc/<pid>/stat 查找进程组中的进程
+func listProcessGroupProc(pgid int) ([]ProcessInfo, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var rs []ProcessInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			// 进程可能已经退出
+			continue
+		}
+		// stat 的格式为 "pid (comm) state ppid pgrp ..."，comm 中可能包含空格与括号
+		stat := string(data)
+		start, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+		if start < 0 || end < start {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		if len(fields) < 3 {
+			continue
+		}
+		if group, err := strconv.Atoi(fields[2]); err == nil && group == pgid {
+			rs = append(rs, ProcessInfo{Pid: pid, Command: stat[start+1 : end]})
+		}
 	}
+	return rs, nil
+}
 
-	// 如果进程仍然存在，发送SIGKILL信号
-	return KillProcessGroup(pid)
+// listProcessGroupPs 通过 ps 命令查找进程组中的进程
+func listProcessGroupPs(pgid int) ([]ProcessInfo, error) {
+	output, err := exec.Command("ps", "-A", "-o", "pid=,pgid=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	var rs []ProcessInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		group, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil || group != pgid {
+			continue
+		}
+		rs = append(rs, ProcessInfo{Pid: pid, Command: strings.Join(fields[2:], " ")})
+	}
+	return rs, nil
 }
 
 // IsProcessExists 检查指定PID的进程是否存在
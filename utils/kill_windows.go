@@ -67,3 +67,15 @@ func SendSignalToGroup(pid int, signal int) error {
 
 	return nil
 }
+
+// GracefullyKillGroup 终止进程组
+// Windows 中没有 SIGTERM，直接调用 KillProcessGroup
+func GracefullyKillGroup(pid int, timeout int) error {
+	return KillProcessGroup(pid)
+}
+
+// ListProcessGroup 列出进程组中的进程
+// Windows 中只返回组长进程
+func ListProcessGroup(pgid int) ([]ProcessInfo, error) {
+	return []ProcessInfo{{Pid: pgid}}, nil
+}
@@ -0,0 +1,17 @@
+package utils
+
+import "strconv"
+
+// ProcessInfo 表示一个进程的基本信息
+type ProcessInfo struct {
+	Pid     int    // 进程ID
+	Command string // 进程的命令名称，可能为空
+}
+
+// String 返回进程的字符串表示，格式为 "命令(pid)"
+func (p ProcessInfo) String() string {
+	if p.Command == "" {
+		return strconv.Itoa(p.Pid)
+	}
+	return p.Command + "(" + strconv.Itoa(p.Pid) + ")"
+}
@@ -0,0 +1,201 @@
+package pom_component_parsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// VersionRange 表示 Maven 的版本要求，可以是范围（如 [1.2,2.0)、(,1.0],[1.5,)）或软性版本（如 1.0）
+// 与 Maven 的 VersionRange 一致，多个区间以逗号连接表示并集
+type VersionRange struct {
+	Recommended  string        // 软性版本要求，仅在没有区间时使用，如 1.0
+	Restrictions []Restriction // 区间列表，为空表示不限制版本
+}
+
+// Restriction 表示版本范围中的一个区间，上下界为空表示无界
+type Restriction struct {
+	Lower          string // 下界
+	LowerInclusive bool   // 是否包含下界
+	Upper          string // 上界
+	UpperInclusive bool   // 是否包含上界
+}
+
+// ErrInvalidVersionRange 表示版本范围的格式不正确
+var ErrInvalidVersionRange = errors.New("无效的版本范围")
+
+// IsVersionRange 判断版本字符串是否为范围形式（以 [ 或 ( 开头）
+func IsVersionRange(version string) bool {
+	version = strings.TrimSpace(version)
+	return strings.HasPrefix(version, "[") || strings.HasPrefix(version, "(")
+}
+
+// ParseVersionRange 解析 Maven 版本范围，规则与 Maven VersionRange.createFromVersionSpec 一致，
+// 只是拒绝包含多个逗号的区间（如 [1.0,2.0,3.0]），Maven 会把第一个逗号之后的全部内容当作上界
+func ParseVersionRange(spec string) (VersionRange, error) {
+	spec = precompiledRegexp.ReplaceAllString(spec, "")
+	if !IsVersionRange(spec) {
+		if spec == "" {
+			return VersionRange{}, fmt.Errorf("%w: 版本为空", ErrInvalidVersionRange)
+		}
+		return VersionRange{Recommended: spec}, nil
+	}
+
+	var rs VersionRange
+	rest := spec
+	for strings.HasPrefix(rest, "[") || strings.HasPrefix(rest, "(") {
+		idx1 := strings.Index(rest, ")")
+		idx2 := strings.Index(rest, "]")
+		idx := idx2
+		if idx2 < 0 || (idx1 >= 0 && idx1 < idx2) {
+			idx = idx1
+		}
+		if idx < 0 {
+			return VersionRange{}, fmt.Errorf("%w: %s 缺少右括号", ErrInvalidVersionRange, spec)
+		}
+
+		restriction, err := parseRestriction(rest[:idx+1])
+		if err != nil {
+			return VersionRange{}, fmt.Errorf("%w: %s", err, spec)
+		}
+		if n := len(rs.Restrictions); n > 0 {
+			prev := rs.Restrictions[n-1]
+			if prev.Upper != "" && (restriction.Lower == "" || CompareVersions(restriction.Lower, prev.Upper) < 0) {
+				return VersionRange{}, fmt.Errorf("%w: %s 中的区间存在重叠", ErrInvalidVersionRange, spec)
+			}
+		}
+		rs.Restrictions = append(rs.Restrictions, restriction)
+
+		rest = strings.TrimPrefix(rest[idx+1:], ",")
+	}
+
+	if rest != "" {
+		return VersionRange{}, fmt.Errorf("%w: %s 中存在多余的内容 %q", ErrInvalidVersionRange, spec, rest)
+	}
+	return rs, nil
+}
+
+// parseRestriction 解析单个区间，如 [1.0,2.0)、[1.0]。
+// 与 Maven 一致，无界端可以使用方括号（如 [,1.0]），上下界相同时必须是闭区间 [1.0,1.0]
+func parseRestriction(spec string) (Restriction, error) {
+	lowerInclusive := strings.HasPrefix(spec, "[")
+	upperInclusive := strings.HasSuffix(spec, "]")
+	body := spec[1 : len(spec)-1]
+
+	lower, upper, hasComma := strings.Cut(body, ",")
+	if !hasComma {
+		// 单个版本必须使用 [] 包围，表示精确匹配
+		if !lowerInclusive || !upperInclusive {
+			return Restriction{}, fmt.Errorf("%w: 单个版本 %s 必须使用 [] 包围", ErrInvalidVersionRange, spec)
+		}
+		if body == "" {
+			return Restriction{}, fmt.Errorf("%w: %s 版本为空", ErrInvalidVersionRange, spec)
+		}
+		return Restriction{Lower: body, LowerInclusive: true, Upper: body, UpperInclusive: true}, nil
+	}
+	if strings.Contains(upper, ",") {
+		return Restriction{}, fmt.Errorf("%w: %s 包含多个逗号", ErrInvalidVersionRange, spec)
+	}
+	if lower != "" && upper != "" {
+		cmp := CompareVersions(lower, upper)
+		if cmp > 0 {
+			return Restriction{}, fmt.Errorf("%w: %s 的下界大于上界", ErrInvalidVersionRange, spec)
+		}
+		if cmp == 0 && (!lowerInclusive || !upperInclusive) {
+			return Restriction{}, fmt.Errorf("%w: %s 的上下界相同但不是闭区间", ErrInvalidVersionRange, spec)
+		}
+	}
+
+	return Restriction{
+		Lower:          lower,
+		LowerInclusive: lowerInclusive,
+		Upper:          upper,
+		UpperInclusive: upperInclusive,
+	}, nil
+}
+
+// ContainsVersion 判断区间是否包含指定版本
+func (r Restriction) ContainsVersion(version string) bool {
+	if r.Lower != "" {
+		cmp := CompareVersions(version, r.Lower)
+		if cmp < 0 || (cmp == 0 && !r.LowerInclusive) {
+			return false
+		}
+	}
+	if r.Upper != "" {
+		cmp := CompareVersions(version, r.Upper)
+		if cmp > 0 || (cmp == 0 && !r.UpperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回区间的 Maven 语法表示
+func (r Restriction) String() string {
+	if r.LowerInclusive && r.UpperInclusive && r.Lower != "" && r.Lower == r.Upper {
+		return "[" + r.Lower + "]"
+	}
+	var sb strings.Builder
+	if r.LowerInclusive {
+		sb.WriteString("[")
+	} else {
+		sb.WriteString("(")
+	}
+	sb.WriteString(r.Lower + "," + r.Upper)
+	if r.UpperInclusive {
+		sb.WriteString("]")
+	} else {
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// IsRange 判断是否包含区间限制，软性版本返回 false
+func (v VersionRange) IsRange() bool {
+	return len(v.Restrictions) > 0
+}
+
+// ContainsVersion 判断版本是否满足要求，软性版本接受任何版本
+func (v VersionRange) ContainsVersion(version string) bool {
+	if !v.IsRange() {
+		return true
+	}
+	for _, r := range v.Restrictions {
+		if r.ContainsVersion(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchVersion 从可用版本中选出满足要求的最高版本
+// 软性版本直接返回 Recommended；没有满足要求的版本时返回 false
+func (v VersionRange) MatchVersion(available []string) (string, bool) {
+	if !v.IsRange() {
+		return v.Recommended, v.Recommended != ""
+	}
+
+	var matched []string
+	for _, version := range available {
+		if v.ContainsVersion(version) {
+			matched = append(matched, version)
+		}
+	}
+	if len(matched) == 0 {
+		return "", false
+	}
+	return LatestVersion(matched...), true
+}
+
+// String 返回版本要求的 Maven 语法表示
+func (v VersionRange) String() string {
+	if !v.IsRange() {
+		return v.Recommended
+	}
+	parts := make([]string, 0, len(v.Restrictions))
+	for _, r := range v.Restrictions {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, ",")
+}
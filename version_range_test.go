@@ -0,0 +1,113 @@
+package pom_component_parsing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersionRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{name: "软性版本", spec: "1.0", want: "1.0"},
+		{name: "精确版本", spec: "[1.0]", want: "[1.0]"},
+		{name: "左闭右开", spec: "[1.2,2.0)", want: "[1.2,2.0)"},
+		{name: "下界无限", spec: "(,1.0]", want: "(,1.0]"},
+		{name: "上界无限", spec: "[1.5,)", want: "[1.5,)"},
+		{name: "并集", spec: "(,1.0],[1.2,)", want: "(,1.0],[1.2,)"},
+		{name: "去除空白", spec: " [1.0 , 2.0] ", want: "[1.0,2.0]"},
+		{name: "区间端点相接", spec: "[1.0,1.1],[1.1,1.2]", want: "[1.0,1.1],[1.1,1.2]"},
+		{name: "下界无限使用方括号", spec: "[,1.0]", want: "[,1.0]"},
+		{name: "上界无限使用方括号", spec: "[1.0,]", want: "[1.0,]"},
+		{name: "上下界相同的闭区间", spec: "[1.0,1.0]", want: "[1.0]"},
+		{name: "空版本", spec: "", wantErr: true},
+		{name: "缺少右括号", spec: "[1.0,2.0", wantErr: true},
+		{name: "单个版本使用圆括号", spec: "(1.0)", wantErr: true},
+		{name: "空区间", spec: "[]", wantErr: true},
+		{name: "多个逗号", spec: "[1.0,2.0,3.0]", wantErr: true},
+		{name: "下界大于上界", spec: "[2.0,1.0)", wantErr: true},
+		{name: "上下界相同的开区间", spec: "(1.0,1.0)", wantErr: true},
+		{name: "上下界相同的半开区间", spec: "[1.0,1.0)", wantErr: true},
+		{name: "等价版本的半开区间", spec: "(1.0,1.0.0]", wantErr: true},
+		{name: "区间重叠", spec: "[1.0,2.0],[1.5,3.0]", wantErr: true},
+		{name: "多余内容", spec: "[1.0,2.0]1.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersionRange(tt.spec)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidVersionRange) {
+					t.Errorf("ParseVersionRange(%q) error = %v, want ErrInvalidVersionRange", tt.spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVersionRange(%q) error = %v", tt.spec, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseVersionRange(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionRange_ContainsVersion(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{spec: "[1.2,2.0)", version: "1.2", want: true},
+		{spec: "[1.2,2.0)", version: "1.10", want: true},
+		{spec: "[1.2,2.0)", version: "2.0", want: false},
+		{spec: "[1.2,2.0)", version: "2.0-rc1", want: true},
+		{spec: "(1.2,2.0]", version: "1.2.0", want: false},
+		{spec: "[1.0]", version: "1.0.0", want: true},
+		{spec: "(,1.0],[1.2,)", version: "1.1", want: false},
+		{spec: "(,1.0],[1.2,)", version: "3.0", want: true},
+		{spec: "[,1.0]", version: "0.1", want: true},
+		{spec: "[1.0,]", version: "1.0", want: true},
+		{spec: "1.0", version: "9.9", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec+" "+tt.version, func(t *testing.T) {
+			vr, err := ParseVersionRange(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseVersionRange(%q) error = %v", tt.spec, err)
+			}
+			if got := vr.ContainsVersion(tt.version); got != tt.want {
+				t.Errorf("ContainsVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionRange_MatchVersion(t *testing.T) {
+	available := []string{"1.0", "1.2", "1.10", "2.0-rc1", "2.0", "3.1"}
+	tests := []struct {
+		spec   string
+		want   string
+		wantOk bool
+	}{
+		{spec: "[1.2,2.0)", want: "2.0-rc1", wantOk: true},
+		{spec: "(,1.2)", want: "1.0", wantOk: true},
+		{spec: "(,1.0],[3.0,)", want: "3.1", wantOk: true},
+		{spec: "[4.0,)", wantOk: false},
+		{spec: "1.5", want: "1.5", wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			vr, err := ParseVersionRange(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseVersionRange(%q) error = %v", tt.spec, err)
+			}
+			got, ok := vr.MatchVersion(available)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("MatchVersion() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}